@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// After annotates an init as one to be invoked after the predecessors, other inits, e.g. when they
+// register into shared global state. Otherwise, inits are only guaranteed to be invoked after the
+// constructors. Inits are told apart by their functions, so a predecessor stands for every init of
+// the same function, e.g. closures of the same function literal. Predecessors mayn't belong to a
+// later phase (see the Phase function), and the constraints mustn't form a cycle. The result is to
+// be provided in place of the init.
+func After(init interface{}, predecessors ...interface{}) interface{} {
+	return annotate(init, func(annotation *annotation) {
+		annotation.after = append(
+			append([]interface{}(nil), annotation.after...),
+			predecessors...,
+		)
+	})
+}
+
+// orderInits orders inits so each is preceded by its predecessors (see the After function) while
+// keeping the order they were provided in otherwise.
+func (p *Plan) orderInits() error {
+	if len(p.predecessors) == 0 {
+		return nil
+	}
+
+	byFunc := make(map[uintptr][]*node, len(p.inits))
+	for _, init := range p.inits {
+		pointer := init.initializer.Pointer()
+		byFunc[pointer] = append(byFunc[pointer], init)
+	}
+	for init, funcs := range p.predecessors {
+		for _, predecessor := range funcs {
+			value := reflect.ValueOf(annotationOf(predecessor).initializer)
+			var matches []*node
+			if value.Kind() == reflect.Func {
+				matches = byFunc[value.Pointer()]
+			}
+			if len(matches) == 0 {
+				return fmt.Errorf(
+					"init '%s' is to be invoked after '%v', which isn't an init",
+					init.name(),
+					predecessor,
+				)
+			}
+
+			for _, match := range matches {
+				if match == init {
+					continue
+				}
+				if match.phase > init.phase {
+					return fmt.Errorf(
+						"init '%s' is to be invoked after '%s' of a later phase",
+						init.name(),
+						match.name(),
+					)
+				}
+				init.after = append(init.after, match)
+			}
+		}
+	}
+
+	var (
+		states  = make(map[*node]visitState, len(p.inits))
+		ordered = make([]*node, 0, len(p.inits))
+		visit   func(*node) error
+	)
+	visit = func(init *node) error {
+		switch states[init] {
+		case visiting:
+			return fmt.Errorf("init order %w", ErrCycle)
+		case visited:
+			return nil
+		}
+		states[init] = visiting
+
+		for _, predecessor := range init.after {
+			if err := visit(predecessor); err != nil {
+				return err
+			}
+		}
+
+		states[init] = visited
+		ordered = append(ordered, init)
+
+		return nil
+	}
+	for _, init := range p.inits {
+		if err := visit(init); err != nil {
+			return err
+		}
+	}
+	p.inits = ordered
+
+	return nil
+}
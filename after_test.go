@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestAfter(t *testing.T) {
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) {
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		order = append(order, name)
+	}
+	registerRoutes := func() {
+
+		record("routes")
+	}
+	registerMiddleware := func() {
+
+		record("middleware")
+	}
+
+	for _, workers := range []int{0, 2} {
+		workers := workers
+
+		t.Run("ordered", func(t *testing.T) {
+
+			order = nil
+
+			app, err := chariot.New(
+				chariot.With(chariot.After(registerRoutes, registerMiddleware), registerMiddleware),
+				chariot.WithParallelInits(workers),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			app.Shutdown()
+
+			if !reflect.DeepEqual(order, []string{"middleware", "routes"}) {
+				t.Fatal(order)
+			}
+		})
+	}
+
+	t.Run("cycle", func(t *testing.T) {
+
+		first := func() {}
+		second := func() error {
+
+			return nil
+		}
+
+		_, err := chariot.New(chariot.With(chariot.After(first, second), chariot.After(second, first)))
+		if !errors.Is(err, chariot.ErrCycle) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("not-an-init", func(t *testing.T) {
+
+		_, err := chariot.New(chariot.With(chariot.After(registerRoutes, func() {})))
+		if err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("constructor", func(t *testing.T) {
+
+		_, err := chariot.New(chariot.With(chariot.After(func() *C {
+
+			return new(C)
+		}, registerRoutes), registerRoutes))
+		if err == nil {
+			t.FailNow()
+		}
+	})
+}
@@ -0,0 +1,160 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"reflect"
+)
+
+const (
+	negErrorAt  = -3
+	autoErrorAt = -2
+	noError     = -1
+)
+
+// annotation is an initializer along with metadata altering the way it's treated.
+type annotation struct {
+	initializer interface{}
+	errorAt     int
+	lazy        bool
+	startAfter  []reflect.Type
+	retry       *retryPolicy
+	scoped      bool
+	factory     bool
+	after       []interface{}
+	description string
+	override    bool
+	snapshot    bool
+	as          reflect.Type
+	onShutdown  interface{}
+	reExported  bool
+	fromValue   bool
+}
+
+// ErrorAt annotates an initializer with the position of the error among the values it returns,
+// counting from 0. The rest of the values are treated as components regardless of whether they
+// conform to the error interface. A negative position causes an error. The result is to be
+// provided in place of the initializer.
+func ErrorAt(initializer interface{}, position int) interface{} {
+	return annotate(initializer, func(annotation *annotation) {
+		annotation.errorAt = errorPosition(position)
+	})
+}
+
+// errorPosition maps a negative position of an error to a sentinel of its own, so it neither
+// collides with the other sentinels nor indexes the values an initializer returns.
+func errorPosition(position int) int {
+	if position < 0 {
+		return negErrorAt
+	}
+
+	return position
+}
+
+// NoError annotates an initializer as one not returning an error, so all the values it returns are
+// treated as components regardless of whether they conform to the error interface. The result is
+// to be provided in place of the initializer.
+func NoError(initializer interface{}) interface{} {
+	return annotate(initializer, func(annotation *annotation) {
+		annotation.errorAt = noError
+	})
+}
+
+// Lazy annotates a constructor as one to be invoked on demand: when a component it constructs is
+// first retrieved rather than during the initialization of an app. A constructor an eagerly
+// constructed component or an init depends on is invoked during the initialization regardless. The
+// result is to be provided in place of the constructor.
+func Lazy(constructor interface{}) interface{} {
+	return annotate(constructor, func(annotation *annotation) {
+		annotation.lazy = true
+	})
+}
+
+// StartAfter annotates a constructor of runners so that they're started only once the runners of
+// the types are ready (see the Readier interface), e.g. a message consumer is started after an
+// admin server. The types must be of Runner-conformant components, and the constraints mustn't
+// form a cycle. The result is to be provided in place of the constructor.
+func StartAfter(constructor interface{}, types ...reflect.Type) interface{} {
+	return annotate(constructor, func(annotation *annotation) {
+		annotation.startAfter = append(
+			append([]reflect.Type(nil), annotation.startAfter...),
+			types...,
+		)
+	})
+}
+
+// Scoped annotates a constructor as one constructing components anew in each scope of an app (see
+// the Scope method) rather than once in the app itself. The components of the constructor may
+// depend on components of the app and the ones provided to a scope, e.g. a request, while the
+// components of the app mayn't depend on them. Only the direct scopes of an app construct the
+// components; scopes of a scope inherit the ones of the latter. The result is to be provided in
+// place of the constructor.
+func Scoped(constructor interface{}) interface{} {
+	return annotate(constructor, func(annotation *annotation) {
+		annotation.scoped = true
+	})
+}
+
+// Override annotates a constructor of a scope as one replacing the components of the app the scope
+// inherits, e.g. to substitute a fake for a test. Components of the scope, as well as its Retrieve
+// method, observe the replacements, while the components of the app keep the inherited ones. A
+// constructor of anything but a scope or of a component that isn't inherited causes an error. The
+// result is to be provided in place of the constructor.
+func Override(constructor interface{}) interface{} {
+	return annotate(constructor, func(annotation *annotation) {
+		annotation.override = true
+	})
+}
+
+// Describe annotates an initializer with a human description, e.g. "primary Postgres pool", that
+// accompanies its name in the errors it causes, is prepended to the ones it returns, and is
+// reported for its components (see the Description method). The result is to be provided in place
+// of the initializer.
+func Describe(initializer interface{}, description string) interface{} {
+	return annotate(initializer, func(annotation *annotation) {
+		annotation.description = description
+	})
+}
+
+func annotate(initializer interface{}, apply func(*annotation)) *annotation {
+	annotated := annotationOf(initializer)
+	apply(&annotated)
+
+	return &annotated
+}
+
+// annotationOf returns a copy of the annotation of an initializer; an initializer provided as is
+// gets the default one.
+func annotationOf(initializer interface{}) annotation {
+	switch annotated := initializer.(type) {
+	case *annotation:
+		return *annotated
+	case *Provider:
+		return annotated.annotation
+	}
+
+	return annotation{
+		initializer: initializer,
+		errorAt:     autoErrorAt,
+	}
+}
@@ -26,20 +26,75 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"os"
-	"os/signal"
 	"reflect"
+	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // App is a DI container supplemented with a compact set of related logic aimed to facilitate the
-// process of initialization of applications composed of multiple components or modules.
+// process of initialization of applications composed of multiple components or modules. An app is
+// a handle to a state shared among its copies: copying an app is cheap, and all copies observe and
+// affect the same components, runners and shutdowners. The zero value isn't a valid app; use the
+// Valid method to tell whether an app was initialized.
 type App struct {
-	ctx         context.Context
-	cancel      func()
-	components  map[reflect.Type]*component
-	runners     []Runner
-	shutdowners []Shutdowner
+	*state
+}
+
+type state struct {
+	// The counters are kept first for 64-bit atomic operations to be aligned on 32-bit platforms.
+	overrides    int64
+	retrievals   int64
+	scopes       int64
+	parent       App
+	funcOptions  []Option
+	ctx          context.Context
+	cancel       func()
+	mu           sync.RWMutex
+	components   map[reflect.Type]*component
+	lazyMus      map[*node]*sync.Mutex
+	rejectNil    bool
+	identityCtxs bool
+	slowInit     *slowInitPolicy
+	exporters    []Exporter
+	observers    observerSet
+	tracer       *resolutionTracer
+	crashes      *crashReporter
+	retries      *retryTracker
+	initTimeout  time.Duration
+	initWorkers  int
+	runners      []*managedRunner
+	shutdowners  []Shutdowner
+	swaps        map[reflect.Type]chan struct{}
+	appState     AppState
+	failed       bool
+	signal       bool
+	suspended    bool
+	snapshot     map[reflect.Type]reflect.Value
+	toggles      *DebugToggles
+	values       Values
+	metadata     map[string]string
+	instances    map[string][]App
+	stateChanged chan struct{}
+	runCtx       context.Context
+	aliveCtx     context.Context
+	cancelAlive  func()
+	cancelRun    func()
+	runExited    chan struct{}
+	ready        chan struct{}
+	draining     chan struct{}
+	drainOnce    sync.Once
+	signals      *Signals
+	spawner      *Spawner
+	stopToken    *StopToken
+	scoped       []interface{}
+	runDefs      []RunOption
+	shutdownDefs []ShutdownOption
+	closed       chan struct{}
+	shutdownOnce sync.Once
 }
 
 type (
@@ -55,82 +110,93 @@ type (
 	Shutdowner interface {
 		Shutdown(context.Context)
 	}
+
+	// Readier stands for a Runner-conformant component reporting when it's ready, e.g. a server
+	// that has started listening, by closing the channel the Ready method returns. A runner that
+	// doesn't conform to the interface is deemed ready as soon as it's started.
+	Readier interface {
+		Ready() <-chan struct{}
+	}
+
+	// Named stands for a component that has a name to be identified by in errors and other
+	// reports. A component that doesn't conform to the interface is identified by its type.
+	Named interface {
+		Name() string
+	}
 )
 
 // FuncRunner is a quick way to introduce a Runner-conformant component.
 type FuncRunner func(context.Context) error
 
+// FuncShutdowner is a quick way to introduce a Shutdowner-conformant component.
+type FuncShutdowner func(context.Context)
+
 // New instantiates a new app, namely to initialize components provided as a set (effectively, a
 // DAG) of initializers automatically resolving dependencies among them. A component is an instance
 // of a type. No restrictions on types except 1) they must be distinct and 2) a special treatment
 // of the error type which is further described. An initializer is a function. Can be of two
 // flavors: one returning 1..N components called a constructor and another returning 0 components
 // called an init (borrowing the term from Go). Both can have dependencies listed as arguments they
-// take barring a variadic one. A missing dependency causes an error. Circular dependencies are
-// prohibited. Both can return an error as the last returning value that won't be treated as a
-// component. An error returned this way disrupts the instantiation process causing the function to
-// return with the error. In the more general case, not only an error originating in the process is
-// returned but the Shutdown method is invoked to ensure a graceful clean-up. Constructors are
-// invoked first followed by inits (akin to how instantiation of global vars and invocation of
-// init funcs are arranged in Go). The app is prepackaged with a context.Context component that is
-// associated with it and cancelled when the SIGINT signal is caught or the app has been shut down.
-// A few options are there to control the behavior. Lastly, components conformant to the Runner
-// and/or the Shutdowner interfaces are collected and stored for a later usage when the app's
-// corresponding methods are invoked.
-func New(funcOptions ...Option) (_ App, err error) {
-	var options options
-	for _, option := range funcOptions {
-		option(&options)
-	}
-
-	app := App{
-		components: make(map[reflect.Type]*component, len(options.initializers)+1),
-	}
-
-	app.initializeCtx(options.signals)
-	cancel := app.setCtxComponent(options.ctx)
-	defer cancel()
-	defer app.resetCtxComponent()
-
-	defer func() {
-		if err == nil {
-			return
-		}
-		var ctx context.Context
-		app.Retrieve(&ctx)
-		app.Shutdown(WithShutdownContext(ctx))
-	}()
-
-	inits, err := app.initializeComponents(
-		app.mergeComponentsInitializers(options.components, options.initializers),
-	)
+// take barring a variadic one (see the WithVariadicInjection option though). A missing dependency
+// causes an error. Circular dependencies are prohibited. Both can return an error as the last
+// returning value that won't be treated as a component. An error returned this way disrupts the
+// instantiation process causing the function to return with the error. An error-conformant value
+// returned in any other position is deemed ambiguous and causes an error unless the initializer is
+// annotated with either ErrorAt or NoError. In the more general case, not only an error originating
+// in the process is returned but the Shutdown method is invoked to ensure a graceful clean-up.
+// Constructors are invoked first followed by inits (akin to how instantiation of global vars and
+// invocation of init funcs are arranged in Go). The app is prepackaged with a context.Context
+// component that is associated with it and cancelled when the SIGINT signal is caught or the app
+// has been shut down, and a BuildInfo component describing the binary. A few options are there to
+// control the behavior. Lastly, components conformant to the Runner and/or the Shutdowner
+// interfaces are collected and stored for a later usage when the app's corresponding methods are
+// invoked. The function is a shorthand for making a plan with the NewPlan function and building an
+// app out of it.
+func New(funcOptions ...Option) (App, error) {
+	plan, err := NewPlan(funcOptions...)
 	if err != nil {
 		return App{}, err
 	}
-	if err := app.invokeInits(inits); err != nil {
-		return App{}, err
-	}
 
-	return app, nil
+	return plan.build(plan.options.initCtx)
 }
 
 // Run runs previously collected Runner-conformant components in a concurrent manner with respect
 // to errors returned by them in the process. In case of any the context provided to them is
 // cancelled and the method waits till other components finish their work. Errors returned at this
 // stage are collected and an aggregated error is returned (placing the one that triggered the
-// event at the head of the underlying list). In case there was no error the method returns nil.
+// event at the head of the underlying list), each prefixed with the name of the runner that
+// returned it (see the Named interface). In case there was no error the method returns nil. A
+// runner constructed by a constructor annotated with StartAfter is started once the runners it's
+// to start after are ready (see the Readier interface). Once the app has been shut down the method
+// returns ErrAppClosed. An app runs at most once: the method returns ErrAppRunning when invoked
+// concurrently and ErrAppFinished afterwards. Resort to the RunReport method to learn the outcome
+// of each runner.
 func (a App) Run(funcOptions ...RunOption) error {
+	return a.RunReport(funcOptions...).Err
+}
+
+// RunReport runs the app the way the Run method does, and reports the outcome in a structured way.
+func (a App) RunReport(funcOptions ...RunOption) (report RunReport) {
 	var options options
+	for _, option := range a.runDefs {
+		option(&options)
+	}
 	for _, option := range funcOptions {
 		option(&options)
 	}
+	if len(options.errs) != 0 {
+		report.Err = errors.Join(options.errs...)
+
+		return report
+	}
 
 	var (
 		ctx    context.Context
 		cancel func()
 	)
-	if options.ctx != nil {
-		ctx, cancel = context.WithCancel(options.ctx)
+	if options.runCtx != nil {
+		ctx, cancel = context.WithCancel(options.runCtx)
 		go func() {
 			select {
 			case <-a.ctx.Done():
@@ -143,82 +209,327 @@ func (a App) Run(funcOptions ...RunOption) error {
 	}
 	defer cancel()
 
-	var (
-		finished  sync.WaitGroup
-		runErrors = make(chan error, len(a.runners))
-	)
-	finished.Add(len(a.runners))
-	for _, runner := range a.runners {
-		go func(runner Runner) {
-			defer finished.Done()
-			if err := runner.Run(ctx); err != nil {
-				runErrors <- err
-			}
-		}(runner)
+	report.Started = time.Now()
+	report.Metadata = a.Metadata()
+	runners, err := a.startRunning(ctx, cancel)
+	if err != nil {
+		report.Err = err
+
+		return report
 	}
-	go func() {
-		finished.Wait()
-		close(runErrors)
+	defer func() {
+		a.finishRunning(report.Err)
 	}()
-	err, ok := <-runErrors
-	if !ok {
-		return nil
+	defer func() {
+		cancel()
+		for i := len(options.afterRun) - 1; i >= 0; i-- {
+			options.afterRun[i](context.WithoutCancel(ctx), a, report.Err)
+		}
+	}()
+
+	for _, hook := range options.beforeRun {
+		if err := hook(ctx, a); err != nil {
+			report.Err = fmt.Errorf("before-run hook: %w", err)
+
+			return report
+		}
 	}
-	cancel()
-	subsequentErrors := make([]error, 0, len(a.runners)-1)
-	for err := range runErrors {
-		subsequentErrors = append(subsequentErrors, err)
+
+	ready := make(map[reflect.Type]chan struct{}, len(runners))
+	readiness := make([]chan struct{}, 0, len(runners))
+	for _, runner := range runners {
+		runnerReady := make(chan struct{})
+		if runner.componentType != nil {
+			ready[runner.componentType] = runnerReady
+		}
+		readiness = append(readiness, runnerReady)
+	}
+	go a.signalReady(ctx, readiness)
+	if options.monitored() {
+		go monitorBudgets(ctx, runners, options)
 	}
 
-	return errors.Join(append([]error{err}, subsequentErrors...)...)
+	sink := newErrorSink(ctx, cancel, options)
+	if len(runners) == 1 && options.smokeRun <= 0 {
+		a.runInline(ctx, sink, runners[0], ready, readiness[0], options)
+	} else {
+		a.runConcurrently(ctx, cancel, sink, runners, ready, readiness, options)
+	}
+	runErrs, dropped := sink.collected()
+	report.DroppedErrors = dropped
+
+	report.Duration = time.Since(report.Started)
+	report.Runners = make([]RunnerReport, 0, len(runners))
+	for _, runner := range runners {
+		runnerReport := runner.report()
+		report.Runners = append(report.Runners, runnerReport)
+		if runnerReport.Quarantined {
+			report.Quarantined = append(report.Quarantined, runnerReport)
+		}
+	}
+	if len(runErrs) != 0 {
+		// Errors of quarantined runners go last, so that the trigger, if any, leads the error.
+		fatal, quarantined := partitionQuarantined(runErrs)
+		if len(fatal) != 0 {
+			report.Trigger = fatal[0]
+		}
+		report.Err = options.mapExitCode(errors.Join(append(fatal, quarantined...)...))
+		a.crashes.fail(context.WithoutCancel(ctx), "run", report.Err)
+	}
+
+	return report
 }
 
 // Shutdown releases resources associated with an app and invokes Shutdowner-conformant components
 // collected during the initialization of the app in the reverse order they were collected. The
 // latter is akin to the common way of releasing resources of multiple objects in defer statements.
-// Once shut down the app is rendered unusable afterwards.
+// If the app is running at the moment, the context passed to the runners is cancelled first and
+// the method waits for them to exit before invoking any shutdowner; the wait is bounded by both the
+// shutdown context and a timeout (see the WithRunExitTimeout option). Each shutdowner may be bound
+// by an individual timeout as well (see the WithShutdownerTimeout option), and may learn the reason
+// of the shutdown (see the ShutdownReason function). Components that are runners as well are shut
+// down according to a policy (see the WithRunnerShutdown option). Once shut down the app is
+// rendered unusable afterwards: the Run method returns ErrAppClosed, the Retrieve method retrieves
+// nothing, and subsequent calls to the method do nothing.
 func (a App) Shutdown(funcOptions ...ShutdownOption) {
-	var options options
-	for _, option := range funcOptions {
-		option(&options)
-	}
+	a.shutdownOnce.Do(func() {
+		a.shutdown(funcOptions)
+	})
+}
 
+func (a App) shutdown(funcOptions []ShutdownOption) {
+	options := a.shutdownOptions(funcOptions)
+
+	reason := a.shutdownReason()
+	if len(a.exporters) != 0 {
+		defer func(started time.Time) {
+			export(a.exporters, Metric{
+				Name:   "chariot_shutdown_duration_seconds",
+				Kind:   MetricTiming,
+				Labels: map[string]string{"reason": reason.String()},
+				Value:  time.Since(started).Seconds(),
+			})
+		}(time.Now())
+	}
+	cancelRun, runExited := a.close()
+	a.stopToken.stop()
+	if a.parent.Valid() {
+		defer atomic.AddInt64(&a.parent.scopes, -1)
+	}
 	defer a.cancel()
+	defer a.signals.stop()
 
 	var (
 		ctx    context.Context
 		cancel func()
 	)
-	if options.ctx != nil {
-		ctx, cancel = context.WithCancel(options.ctx)
+	if options.shutdownCtx != nil {
+		ctx, cancel = context.WithCancel(options.shutdownCtx)
+		done := ctx.Done()
 		go func() {
 			select {
 			case <-a.ctx.Done():
 				cancel()
-			case <-ctx.Done():
+			case <-done:
 			}
 		}()
 	} else {
 		ctx, cancel = context.WithCancel(a.ctx)
 	}
 	defer cancel()
-	for i := len(a.shutdowners) - 1; i >= 0; i-- {
-		a.shutdowners[i].Shutdown(ctx)
+	if options.runValues {
+		a.mu.RLock()
+		if a.runCtx != nil {
+			ctx = valuesContext{Context: ctx, values: a.runCtx}
+		}
+		a.mu.RUnlock()
+	}
+	if ShutdownReason(ctx) == ReasonUnknown {
+		ctx = context.WithValue(ctx, reasonKey{}, reason)
 	}
+
+	a.mu.RLock()
+	shutdowners := a.shutdowners
+	a.mu.RUnlock()
+	settled := settledShutdowners(shutdowners, options.runnerShutdown)
+
+	if runExited != nil {
+		a.drainBounded(ctx, options)
+
+		cancelRun()
+		a.stopRunners(ctx, shutdowners, settled, options)
+
+		timer := time.NewTimer(options.runExitTimeout)
+		select {
+		case <-runExited:
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		timer.Stop()
+	}
+
+	for i := len(shutdowners) - 1; i >= 0; i-- {
+		if !settled[i] {
+			a.invokeShutdowner(ctx, shutdowners[i], options)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.failed {
+		a.setState(AppFailed)
+	} else {
+		a.setState(AppStopped)
+	}
+}
+
+// shutdownOptions applies the default shutdown options of the app and then the ones provided.
+func (a App) shutdownOptions(funcOptions []ShutdownOption) options {
+	options := options{
+		runExitTimeout: defaultRunExitTimeout,
+		handler:        a.logError,
+	}
+	for _, option := range a.shutdownDefs {
+		option(&options)
+	}
+	for _, option := range funcOptions {
+		option(&options)
+	}
+
+	return options
 }
 
-// Retrieve retrieves a component. A valid value is a pointer to the type of the component.
+// invokeShutdowner invokes a shutdowner bounding it by the per-shutdowner timeout if one is set. A
+// shutdowner exceeding the timeout is reported and left to finish in the background.
+func (a App) invokeShutdowner(ctx context.Context, shutdowner Shutdowner, options options) {
+	if options.shutdownerTimeout <= 0 {
+		a.guardShutdowner(ctx, shutdowner, options)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, options.shutdownerTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.guardShutdowner(ctx, shutdowner, options)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		options.handler(ctx, fmt.Errorf(
+			"shutdowner '%s' exceeded the timeout of %s: %w",
+			nameOf(shutdowner),
+			options.shutdownerTimeout,
+			ErrShutdownTimeout,
+		))
+	}
+}
+
+// guardShutdowner invokes the shutdowner reporting its panic, if the app reports crashes.
+func (a App) guardShutdowner(ctx context.Context, shutdowner Shutdowner, options options) {
+	name := func() string {
+		return nameOf(shutdowner)
+	}
+	shutdown := wrapShutdowner(shutdowner, options.stopMiddleware)
+	if len(options.stopMiddleware) != 0 {
+		ctx = context.WithValue(ctx, shutdownerNameKey{}, name())
+	}
+	started := time.Now()
+	err := a.crashes.guard(ctx, "shutdown", name, func() error {
+		shutdown(ctx)
+
+		return nil
+	})
+	if len(a.observers.shutdown) != 0 {
+		a.observers.observeShutdown(ShutdownEvent{
+			Shutdowner: name(),
+			Started:    started,
+			Duration:   time.Since(started),
+			Err:        err,
+		})
+	}
+	if err != nil {
+		options.handler(ctx, fmt.Errorf("shutdowner '%s': %w", name(), err))
+	}
+}
+
+// Valid reports whether the app was initialized, i.e. it's not the zero value, e.g. one returned
+// alongside an error.
+func (a App) Valid() bool {
+	return a.state != nil
+}
+
+// Retrieve retrieves a component. A valid value is a pointer to the type of the component. Nothing
+// is retrieved once the app has been shut down. A component of a lazy constructor (see the Lazy
+// function) is constructed with the context associated with the app; nothing is retrieved if the
+// construction fails. Resort to the RetrieveCtx method to control the context and learn the error.
 func (a App) Retrieve(ptr interface{}) bool {
+	return a.RetrieveCtx(a.ctx, ptr) == nil
+}
+
+// RetrieveCtx retrieves a component the way the Retrieve method does, except a component of a lazy
+// constructor (see the Lazy function) not constructed yet is constructed on the spot, with the
+// context provided as the context.Context dependency of the lazy constructors invoked in the
+// process. An error is returned if the app has been shut down (ErrAppClosed), there is no such a
+// component, or the construction fails. A Runner-conformant component constructed this way is
+// only run if the app's Run method is invoked afterwards.
+func (a App) RetrieveCtx(ctx context.Context, ptr interface{}) error {
+	if a.isClosed() {
+		return ErrAppClosed
+	}
+
 	value := reflect.ValueOf(ptr).Elem()
 
-	component, found := a.components[value.Type()]
+	atomic.AddInt64(&a.retrievals, 1)
+	owner, component, found := a.lookup(value.Type())
 	if !found {
-		return false
+		a.hintMissing(value.Type())
+
+		return fmt.Errorf("%w '%s'", ErrMissingComponent, value.Type())
+	}
+	defer a.traceRetrieval(value.Type(), time.Now())
+
+	componentValue, err := owner.valueFor(ctx, component, nil)
+	if err != nil {
+		return err
+	}
+	value.Set(componentValue)
+
+	return nil
+}
+
+// Dependencies reports the types a component depended on during its construction. A valid value is
+// a pointer to the type of the component. The information is only retained with the
+// WithIntrospection option provided; otherwise, the method reports nothing.
+func (a App) Dependencies(ptr interface{}) ([]reflect.Type, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	component, found := a.components[reflect.TypeOf(ptr).Elem()]
+	if !found || component.node == nil {
+		return nil, false
 	}
 
-	value.Set(component.value)
+	return append([]reflect.Type(nil), component.node.dependencies...), true
+}
 
-	return true
+// Description reports the description a component's initializer is annotated with (see the
+// Describe function). A valid value is a pointer to the type of the component.
+func (a App) Description(ptr interface{}) (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	component, found := a.components[reflect.TypeOf(ptr).Elem()]
+	if !found || component.description == "" {
+		return "", false
+	}
+
+	return component.description, true
 }
 
 // Run delegates the execution to the receiver.
@@ -226,8 +537,149 @@ func (r FuncRunner) Run(ctx context.Context) error {
 	return r(ctx)
 }
 
-func (a *App) initializeCtx(signals []os.Signal) {
-	a.ctx, a.cancel = signal.NotifyContext(context.Background(), append(signals, os.Interrupt)...)
+// Shutdown delegates the execution to the receiver.
+func (s FuncShutdowner) Shutdown(ctx context.Context) {
+	s(ctx)
+}
+
+func (a App) startRunning(ctx context.Context, cancel func()) ([]*managedRunner, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.isClosed() {
+		return nil, ErrAppClosed
+	}
+
+	switch a.appState {
+	case AppRunning:
+		return nil, ErrAppRunning
+	case AppStopped, AppFailed:
+		return nil, ErrAppFinished
+	}
+	a.setState(AppRunning)
+	a.runCtx = ctx
+	a.cancelRun = cancel
+	a.bindRunContext(ctx)
+	a.runExited = make(chan struct{})
+
+	return a.runners, nil
+}
+
+// Ready returns a channel that's closed once all the runners of the app are ready in the course of
+// its run (see the Readier interface).
+func (a App) Ready() <-chan struct{} {
+	return a.ready
+}
+
+// signalReady closes the app's ready channel once all the runners are ready.
+func (a App) signalReady(ctx context.Context, readiness []chan struct{}) {
+	for _, ready := range readiness {
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return
+		}
+	}
+	close(a.ready)
+}
+
+func (a App) finishRunning(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch {
+	case a.appState == AppDraining:
+	case err != nil && !cancelledOnly(err):
+		a.setState(AppFailed)
+	default:
+		a.setState(AppStopped)
+	}
+	close(a.runExited)
+}
+
+// close marks the app closed and reports the means to stop it from running if it's running.
+func (a App) close() (func(), <-chan struct{}) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	close(a.closed)
+
+	running := a.appState == AppRunning
+	a.setState(AppDraining)
+	if !running {
+		return nil, nil
+	}
+
+	return a.cancelRun, a.runExited
+}
+
+func (a App) isClosed() bool {
+	select {
+	case <-a.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a App) initializeCtx(base context.Context, signals []os.Signal, detached bool) {
+	if a.parent.Valid() {
+		a.ctx, a.cancel = context.WithCancel(a.parent.ctx)
+
+		return
+	}
+
+	if base == nil {
+		base = context.Background()
+	}
+	if detached {
+		a.ctx, a.cancel = context.WithCancel(context.WithoutCancel(base))
+
+		return
+	}
+	a.ctx, a.cancel = notifyContext(base, signals, a.signalled)
+}
+
+// signalled records that the app has received a signal, and drains the app if it's running, before
+// its context is cancelled.
+func (a App) signalled() {
+	a.mu.Lock()
+	a.signal = true
+	running := a.appState == AppRunning
+	a.mu.Unlock()
+
+	if running {
+		a.drainBounded(context.WithValue(a.ctx, reasonKey{}, ReasonSignal), a.shutdownOptions(nil))
+	}
+}
+
+// lookup finds a component either among the app's own components or the inherited ones, and
+// reports the app owning it.
+func (a App) lookup(componentType reflect.Type) (App, *component, bool) {
+	for owner := a; owner.Valid(); owner = owner.parent {
+		owner.mu.RLock()
+		component, found := owner.components[componentType]
+		owner.mu.RUnlock()
+		if found {
+			return owner, component, true
+		}
+	}
+
+	return App{}, nil, false
+}
+
+// componentTypes reports the types of both the app's own and the inherited components.
+func (a App) componentTypes() []reflect.Type {
+	var types []reflect.Type
+	for owner := a; owner.Valid(); owner = owner.parent {
+		owner.mu.RLock()
+		for componentType := range owner.components {
+			types = append(types, componentType)
+		}
+		owner.mu.RUnlock()
+	}
+
+	return types
 }
 
 func (a App) setCtxComponent(ctx context.Context) func() {
@@ -244,7 +696,7 @@ func (a App) setCtxComponent(ctx context.Context) func() {
 	} else {
 		ctx = a.ctx
 	}
-	a.components[reflect.TypeOf((*context.Context)(nil)).Elem()] = &component{
+	a.components[ctxType] = &component{
 		value: reflect.ValueOf(ctx),
 	}
 
@@ -252,190 +704,364 @@ func (a App) setCtxComponent(ctx context.Context) func() {
 }
 
 func (a App) resetCtxComponent() {
-	a.components[reflect.TypeOf((*context.Context)(nil)).Elem()] = &component{
-		value: reflect.ValueOf(a.ctx),
-	}
-}
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-func (App) mergeComponentsInitializers(components, initializers []interface{}) []interface{} {
-	for _, component := range components {
-		constructor := reflect.MakeFunc(
-			reflect.FuncOf(
-				nil,
-				[]reflect.Type{
-					reflect.TypeOf(component),
-				},
-				false,
-			),
-			func([]reflect.Value) []reflect.Value {
-				return []reflect.Value{
-					reflect.ValueOf(component),
-				}
-			},
-		)
-		initializers = append(initializers, constructor.Interface())
+	a.components[ctxType] = &component{
+		value: reflect.ValueOf(a.ctx),
 	}
-
-	return initializers
 }
 
-func (a *App) collectComponents(initializers []interface{}) ([]initFunc, error) {
-	var inits []initFunc
-	for _, initializer := range initializers {
-		initializerType := reflect.TypeOf(initializer)
+func (a App) invokeConstructors(constructors []*node) error {
+	for _, constructor := range constructors {
+		if outs, ok := a.restore(constructor); ok {
+			a.store(constructor, outs)
 
-		num := initializerType.NumIn()
-		if initializerType.IsVariadic() {
-			num--
-		}
-		var dependencies []reflect.Type
-		for i := 0; i < num; i++ {
-			dependencies = append(dependencies, initializerType.In(i))
+			continue
 		}
 
-		num = initializerType.NumOut()
-		if last := num - 1; last >= 0 && initializerType.Out(last).Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			num = last
+		ins, err := a.ins(constructor)
+		if err != nil {
+			return err
 		}
-		if num == 0 {
-			inits = append(inits, initFunc{
-				dependencies: dependencies,
-				init:         reflect.ValueOf(initializer),
-			})
 
-			continue
+		if err := a.construct(constructor, ins); err != nil {
+			return err
 		}
+	}
 
-		for i := 0; i < num; i++ {
-			componentType := initializerType.Out(i)
+	return nil
+}
 
-			if _, ok := a.components[componentType]; ok {
-				return nil, fmt.Errorf("duplicating component '%s'", componentType)
-			}
+func (a App) registerLazyConstructors(constructors []*node) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
+	if a.lazyMus == nil {
+		a.lazyMus = make(map[*node]*sync.Mutex, len(constructors))
+	}
+	for _, constructor := range constructors {
+		a.lazyMus[constructor] = new(sync.Mutex)
+		for _, componentType := range constructor.signature.components {
 			a.components[componentType] = &component{
-				dependencies: dependencies,
-				constructor:  reflect.ValueOf(initializer),
+				node:         constructor,
+				description:  constructor.description,
+				snapshotSafe: constructor.snapshotSafe,
 			}
 		}
 	}
+}
 
-	return inits, nil
+// valueOf reports the value of a component constructing it and its dependencies if needed.
+func (a App) valueOf(ctx context.Context, component *component) (reflect.Value, error) {
+	a.mu.RLock()
+	value, node := component.value, component.node
+	a.mu.RUnlock()
+	if value.IsValid() {
+		return value, nil
+	}
+
+	defer a.lockLazy(node)()
+
+	return a.constructLazily(ctx, component, node)
 }
 
-func (a *App) initializeComponents(initializers []interface{}) ([]initFunc, error) {
-	inits, err := a.collectComponents(initializers)
-	if err != nil {
-		return nil, err
+// lockLazy locks the lazy constructor, if it's one, reporting the function unlocking it. Every lazy
+// constructor is locked on its own, so that one may retrieve components of others from the app
+// while being invoked.
+func (a App) lockLazy(constructor *node) func() {
+	a.mu.RLock()
+	mu, ok := a.lazyMus[constructor]
+	a.mu.RUnlock()
+	if !ok {
+		return func() {}
 	}
 
-	cycle := map[reflect.Type]struct{}{}
-	for componentType, component := range a.components {
-		cycle[componentType] = struct{}{}
+	mu.Lock()
 
-		if err := a.initializeComponent(component, cycle); err != nil {
-			return nil, err
+	return mu.Unlock
+}
+
+func (a App) constructLazily(
+	ctx context.Context,
+	component *component,
+	node *node,
+) (reflect.Value, error) {
+	a.mu.RLock()
+	value := component.value
+	a.mu.RUnlock()
+	if value.IsValid() {
+		return value, nil
+	}
+
+	if outs, ok := a.restore(node); ok {
+		a.store(node, outs)
+
+		a.mu.RLock()
+		defer a.mu.RUnlock()
+
+		return component.value, nil
+	}
+
+	ins := make([]reflect.Value, 0, len(node.dependencies))
+	for _, dependencyType := range node.dependencies {
+		in, err := a.lazyDependency(ctx, dependencyType, node)
+		if err != nil {
+			return reflect.Value{}, err
 		}
+		ins = append(ins, in)
+	}
 
-		delete(cycle, componentType)
+	if err := a.construct(node, a.identify(node, ins)); err != nil {
+		return reflect.Value{}, err
 	}
 
-	return inits, nil
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return component.value, nil
 }
 
-func (a *App) initializeComponent(component *component, cycle map[reflect.Type]struct{}) error {
-	if component.value.IsValid() {
-		return nil
+// lazyDependency reports the value of a dependency of a lazy constructor, the consumer, with the
+// consumer already locked.
+func (a App) lazyDependency(
+	ctx context.Context,
+	dependencyType reflect.Type,
+	consumer *node,
+) (reflect.Value, error) {
+	if dependencyType == ctxType {
+		return reflect.ValueOf(ctx), nil
+	}
+
+	owner, dependency, _ := a.lookup(dependencyType)
+	if owner.state != a.state {
+		return owner.valueFor(ctx, dependency, consumer)
+	}
+
+	dependency.markUsed()
+	a.mu.RLock()
+	value, dependencyNode := dependency.value, dependency.node
+	a.mu.RUnlock()
+	if !value.IsValid() && dependencyNode.factory {
+		resolve := func(dependencyType reflect.Type) (reflect.Value, error) {
+			return a.lazyDependency(ctx, dependencyType, dependencyNode)
+		}
+
+		return a.produce(dependencyNode, consumer, resolve)
 	}
+	defer a.lockLazy(dependencyNode)()
 
-	ins, err := a.ins(component, cycle)
+	return a.constructLazily(ctx, dependency, dependencyNode)
+}
+
+// construct invokes a constructor and stores the components it returns collecting Runner- and
+// Shutdowner-conformant ones.
+func (a App) construct(constructor *node, ins []reflect.Value) error {
+	outs, err := a.call(constructor, ins)
 	if err != nil {
 		return err
 	}
+	a.store(constructor, outs)
 
-	outs := component.constructor.Call(ins)
+	return nil
+}
 
-	last := outs[len(outs)-1]
-	if last.Type().Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-		if !last.IsNil() {
-			return last.Interface().(error)
+// store stores the components a constructor returned collecting Runner- and Shutdowner-conformant
+// ones.
+func (a App) store(constructor *node, outs []reflect.Value) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i, out := range outs {
+		componentType := constructor.signature.components[i]
+		if out.Type() != componentType {
+			// The component is provided as an interface (see the Provider.As method).
+			out = out.Convert(componentType)
+		}
+		if existing, ok := a.components[componentType]; ok {
+			existing.value = out
+		} else {
+			a.components[componentType] = &component{
+				node:         constructor,
+				value:        out,
+				description:  constructor.description,
+				snapshotSafe: constructor.snapshotSafe,
+			}
 		}
-		outs = outs[:len(outs)-1]
+		a.manage(constructor, componentType, out)
 	}
+}
 
-	for _, out := range outs {
-		a.components[out.Type()].value = out
+// call invokes an initializer rejecting nil components and reporting the initializer if it's slow,
+// if the app is to.
+func (a App) call(constructor *node, ins []reflect.Value) ([]reflect.Value, error) {
+	ins, cancel := a.bound(constructor, ins)
+	defer cancel()
 
-		if runner, ok := out.Interface().(Runner); ok {
-			a.runners = append(a.runners, runner)
-		}
+	started := time.Now()
+	outs, err := a.callInitializer(constructor, ins)
+	took := time.Since(started)
+	a.reportSlowInit(constructor, took)
+	if a.toggles.Verbose() {
+		a.logf("invoked initializer '%s' in %s\n", constructor.name(), took)
+	}
+	a.exportInit(constructor, took.Seconds())
+	a.observers.observeInit(constructor, started, took, err)
+	a.traceConstruction(constructor, took)
+	if err != nil && constructor.description != "" {
+		return nil, fmt.Errorf("%s: %w", constructor.description, err)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		if shutdowner, ok := out.Interface().(Shutdowner); ok {
-			a.shutdowners = append(a.shutdowners, shutdowner)
+	if a.rejectNil {
+		for _, out := range outs {
+			if isNil(out) {
+				return nil, fmt.Errorf(
+					"constructor '%s' returned a %w of type '%s'",
+					constructor.name(),
+					ErrNilComponent,
+					out.Type(),
+				)
+			}
 		}
 	}
 
-	return nil
+	return outs, nil
 }
 
-func (a *App) ins(component *component, cycle map[reflect.Type]struct{}) ([]reflect.Value, error) {
-	var ins []reflect.Value
+// manage collects a component if it's Runner- or Shutdowner-conformant. It's to be called with the
+// app locked.
+func (a App) manage(constructor *node, componentType reflect.Type, out reflect.Value) {
+	if constructor.reExported {
+		return
+	}
 
-	for _, dependencyType := range component.dependencies {
-		dependency, ok := a.components[dependencyType]
-		if !ok {
-			return nil, fmt.Errorf("missing dependency '%s'", dependencyType)
+	var managed *managedRunner
+	if runner, ok := out.Interface().(Runner); ok {
+		managed = &managedRunner{
+			Runner:        runner,
+			componentType: componentType,
+			startAfter:    constructor.startAfter,
+			exporters:     a.exporters,
+			observers:     a.observers,
+			crashes:       a.crashes,
 		}
+		a.runners = append(a.runners, managed)
+	}
 
-		if _, ok := cycle[dependencyType]; ok {
-			return nil, errors.New("dependency cycle detected")
+	if shutdowner, ok := out.Interface().(Shutdowner); ok {
+		if managed != nil {
+			shutdowner = runnerShutdowner{Shutdowner: shutdowner, runner: managed}
 		}
-		cycle[dependencyType] = struct{}{}
+		a.shutdowners = append(a.shutdowners, shutdowner)
+	}
+	if hook, ok := constructor.shutdownHook(componentType, out); ok {
+		a.shutdowners = append(a.shutdowners, hook)
+	}
+}
 
-		if err := a.initializeComponent(dependency, cycle); err != nil {
+func (a App) ins(node *node) ([]reflect.Value, error) {
+	ins := make([]reflect.Value, 0, len(node.dependencies))
+	for _, dependencyType := range node.dependencies {
+		owner, dependency, _ := a.lookup(dependencyType)
+
+		in, err := owner.valueFor(owner.ctx, dependency, node)
+		if err != nil {
 			return nil, err
 		}
-		ins = append(ins, dependency.value)
-
-		delete(cycle, dependencyType)
+		ins = append(ins, in)
 	}
 
-	return ins, nil
+	return a.identify(node, ins), nil
 }
 
-func (a App) invokeInits(inits []initFunc) error {
-	for _, init := range inits {
-		var ins []reflect.Value
-		for _, dependency := range init.dependencies {
-			component, ok := a.components[dependency]
-			if !ok {
-				return fmt.Errorf("missing dependency '%s'", dependency)
-			}
+func (a App) releaseConstructionMetadata() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-			ins = append(ins, component.value)
+	for _, component := range a.components {
+		if component.value.IsValid() {
+			component.node = nil
 		}
+	}
+}
 
-		outs := init.init.Call(ins)
-
-		if len(outs) == 0 {
-			continue
-		}
+func (a App) invokeInits(inits []*node) error {
+	if a.initWorkers > 1 && !a.toggles.DeterministicOrder() {
+		return a.invokeInitsConcurrently(inits)
+	}
 
-		if err := outs[0]; !err.IsNil() {
-			return err.Interface().(error)
+	for _, init := range inits {
+		if err := a.invokeInit(init); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-type initFunc struct {
-	dependencies []reflect.Type
-	init         reflect.Value
+func (a App) invokeInit(init *node) error {
+	ins, err := a.ins(init)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.call(init, ins)
+
+	return err
+}
+
+// nameOf reports the name a component is identified by.
+func nameOf(component interface{}) string {
+	if named, ok := component.(Named); ok {
+		return named.Name()
+	}
+
+	return reflect.TypeOf(component).String()
+}
+
+func logError(_ context.Context, err error) {
+	log.Printf("chariot: %s\n", err)
+}
+
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Chan, reflect.Func:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+func funcName(value reflect.Value) string {
+	if function := runtime.FuncForPC(value.Pointer()); function != nil {
+		return function.Name()
+	}
+
+	return value.Type().String()
+}
+
+// valuesContext is a context looking values up in another context as a fallback.
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+// Value returns the value associated with the key by the context, or the fallback one otherwise.
+func (c valuesContext) Value(key interface{}) interface{} {
+	if value := c.Context.Value(key); value != nil {
+		return value
+	}
+
+	return c.values.Value(key)
 }
 
 type component struct {
-	dependencies []reflect.Type
-	constructor  reflect.Value
+	node         *node
 	value        reflect.Value
+	used         int32
+	description  string
+	snapshotSafe bool
 }
@@ -25,7 +25,10 @@ package chariot_test
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rwyyr/chariot"
 )
@@ -56,10 +59,14 @@ type (
 	}
 
 	F struct{}
+
+	G struct{}
 )
 
 type Error struct{}
 
+type namedRunner func(context.Context) error
+
 type Unwrapper interface {
 	Unwrap() []error
 }
@@ -366,10 +373,10 @@ func TestNewApp(t *testing.T) {
 
 		testErr := errors.New("test error")
 
-		app, err := chariot.New(chariot.With(func() (error, error) {
+		app, err := chariot.New(chariot.With(chariot.ErrorAt(func() (error, error) {
 
 			return testErr, nil
-		}))
+		}, 1)))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -384,6 +391,86 @@ func TestNewApp(t *testing.T) {
 		}
 	})
 
+	t.Run("ambiguous-error", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func() (error, error) {
+
+			return nil, nil
+		}))
+		if err != nil {
+			if !strings.Contains(err.Error(), "ambiguous signature") {
+				t.Fatal(err)
+			}
+
+			return
+		}
+		defer app.Shutdown()
+
+		t.FailNow()
+	})
+
+	t.Run("error-first", func(t *testing.T) {
+
+		testErr := errors.New("test error")
+
+		app, err := chariot.New(chariot.With(chariot.ErrorAt(func() (error, *A) {
+
+			return testErr, nil
+		}, 0)))
+		if err != nil {
+			if !errors.Is(err, testErr) {
+				t.Fatal(err)
+			}
+
+			return
+		}
+		defer app.Shutdown()
+
+		t.FailNow()
+	})
+
+	t.Run("negative-error", func(t *testing.T) {
+
+		for _, position := range []int{-1, -2, -3} {
+			app, err := chariot.New(chariot.With(chariot.ErrorAt(func() (*A, error) {
+
+				return new(A), nil
+			}, position)))
+			if err != nil {
+				if !strings.Contains(err.Error(), "negative position") {
+					t.Fatal(err)
+				}
+
+				continue
+			}
+			app.Shutdown()
+
+			t.Fatal(position)
+		}
+	})
+
+	t.Run("no-error", func(t *testing.T) {
+
+		testErr := new(Error)
+
+		app, err := chariot.New(chariot.With(chariot.NoError(func() (*A, *Error) {
+
+			return new(A), testErr
+		})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var retrievedErr *Error
+		switch {
+		case !app.Retrieve(&retrievedErr):
+			t.FailNow()
+		case retrievedErr != testErr:
+			t.Fatal(retrievedErr)
+		}
+	})
+
 	t.Run("cycle", func(t *testing.T) {
 
 		app, err := chariot.New(
@@ -474,6 +561,136 @@ func TestNewApp(t *testing.T) {
 		}
 	})
 
+	t.Run("phase-contexts", func(t *testing.T) {
+
+		type key int
+
+		var (
+			initCtx     = context.WithValue(context.Background(), key(0), "init")
+			runCtx      = context.WithValue(context.Background(), key(0), "run")
+			shutdownCtx = context.WithValue(context.Background(), key(0), "shutdown")
+			phases      []interface{}
+		)
+
+		app, err := chariot.New(
+			chariot.WithPhaseContexts(initCtx, runCtx, shutdownCtx),
+			chariot.With(func(ctx context.Context) A {
+
+				phases = append(phases, ctx.Value(key(0)))
+
+				var a A
+				a.mocks.Run = func(ctx context.Context) error {
+
+					phases = append(phases, ctx.Value(key(0)))
+
+					return nil
+				}
+				a.mocks.Shutdown = func(ctx context.Context) {
+
+					phases = append(phases, ctx.Value(key(0)))
+				}
+
+				return a
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+
+		if !reflect.DeepEqual(phases, []interface{}{"init", "run", "shutdown"}) {
+			t.Fatal(phases)
+		}
+	})
+
+	t.Run("base-ctx", func(t *testing.T) {
+
+		key := new(struct{})
+		base, cancel := context.WithCancel(context.WithValue(context.Background(), key, key))
+
+		app, err := chariot.New(chariot.WithBaseContext(base))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var ctx context.Context
+		if !app.Retrieve(&ctx) {
+			t.FailNow()
+		}
+		if value := ctx.Value(key); value != key {
+			t.Fatal(value)
+		}
+
+		cancel()
+		<-ctx.Done()
+	})
+
+	t.Run("detached-ctx", func(t *testing.T) {
+
+		base, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		app, err := chariot.New(chariot.WithBaseContext(base), chariot.WithDetachedContext())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ctx context.Context
+		if !app.Retrieve(&ctx) {
+			t.FailNow()
+		}
+		if err := ctx.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		app.Shutdown()
+		<-ctx.Done()
+	})
+
+	t.Run("provided-ctx", func(t *testing.T) {
+
+		_, err := chariot.New(chariot.With(func() context.Context {
+
+			return context.Background()
+		}))
+		if !errors.Is(err, chariot.ErrDuplicateComponent) ||
+			!strings.Contains(err.Error(), "WithBaseContext") {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("build-info", func(t *testing.T) {
+
+		var called bool
+
+		app, err := chariot.New(
+			chariot.With(func(info chariot.BuildInfo) {
+
+				called = true
+
+				if info.GoVersion == "" {
+					t.Fatal(info)
+				}
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var info chariot.BuildInfo
+		switch {
+		case !called:
+			t.FailNow()
+		case !app.Retrieve(&info):
+			t.FailNow()
+		}
+	})
+
 	t.Run("component", func(t *testing.T) {
 
 		testA := new(A)
@@ -529,15 +746,61 @@ func TestNewApp(t *testing.T) {
 		}
 	})
 
-	t.Run("variadic", func(t *testing.T) {
+	t.Run("invalid-initializer", func(t *testing.T) {
 
-		testC := new(C)
+		var nilFunc func() *A
+
+		for _, initializer := range []interface{}{
+			nil,
+			new(A),
+			nilFunc,
+		} {
+			app, err := chariot.New(chariot.With(func() *B {
+
+				return new(B)
+			}, initializer))
+			if err != nil {
+				if !strings.Contains(err.Error(), "initializer #2 passed to With at ") {
+					t.Fatal(err)
+				}
+
+				continue
+			}
+			defer app.Shutdown()
+
+			t.Fatal(initializer)
+		}
+	})
+
+	t.Run("nil-component", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.WithComponents(nil))
+		if err != nil {
+			if !strings.Contains(err.Error(), "component #1 passed to WithComponents at ") {
+				t.Fatal(err)
+			}
+
+			return
+		}
+		defer app.Shutdown()
+
+		t.FailNow()
+	})
+
+	t.Run("variadic-injection", func(t *testing.T) {
+
+		testA, testB := new(A), new(B)
+
+		var runners []chariot.Runner
 
 		app, err := chariot.New(
-			chariot.WithComponents(new(A)),
-			chariot.With(func(*A, ...*B) *C {
+			chariot.WithVariadicInjection(),
+			chariot.WithComponents(testA, testB, new(C)),
+			chariot.With(func(c *C, extras ...chariot.Runner) *D {
 
-				return testC
+				runners = extras
+
+				return new(D)
 			}),
 		)
 		if err != nil {
@@ -545,80 +808,346 @@ func TestNewApp(t *testing.T) {
 		}
 		defer app.Shutdown()
 
-		var c *C
 		switch {
-		case !app.Retrieve(&c):
-			t.FailNow()
-		case c != testC:
-			t.FailNow()
+		case len(runners) != 2:
+			t.Fatal(runners)
+		case runners[0] != testA:
+			t.Fatal(runners)
+		case runners[1] != testB:
+			t.Fatal(runners)
 		}
 	})
-}
 
-func TestAppRun(t *testing.T) {
+	t.Run("reject-nil", func(t *testing.T) {
 
-	t.Run("simple-case", func(t *testing.T) {
+		for _, initializer := range []interface{}{
+			func() *A {
 
-		var aCalled, bCalled bool
+				return nil
+			},
+			func() (E, error) {
 
-		app, err := chariot.New(
-			chariot.With(
-				func() A {
+				return nil, nil
+			},
+		} {
+			app, err := chariot.New(
+				chariot.WithRejectNil(),
+				chariot.With(initializer),
+			)
+			if err != nil {
+				if !strings.Contains(err.Error(), "returned a nil") {
+					t.Fatal(err)
+				}
 
-					var a A
-					a.mocks.Run = func(context.Context) error {
+				continue
+			}
+			defer app.Shutdown()
 
-						aCalled = true
+			t.Fatal(initializer)
+		}
+	})
 
-						return nil
-					}
+	t.Run("pointer-receiver-mismatch", func(t *testing.T) {
 
-					return a
-				},
-				func() *B {
+		app, err := chariot.New(chariot.With(func() G {
 
-					var b B
-					b.mocks.Run = func(context.Context) error {
+			return G{}
+		}))
+		if err != nil {
+			if !strings.Contains(err.Error(), "pointer receiver") {
+				t.Fatal(err)
+			}
 
-						bCalled = true
+			return
+		}
+		defer app.Shutdown()
 
-						return nil
-					}
+		t.FailNow()
+	})
 
-					return &b
-				},
-			),
+	t.Run("variadic", func(t *testing.T) {
+
+		testC := new(C)
+
+		app, err := chariot.New(
+			chariot.WithComponents(new(A)),
+			chariot.With(func(*A, ...*B) *C {
+
+				return testC
+			}),
 		)
 		if err != nil {
 			t.Fatal(err)
 		}
 		defer app.Shutdown()
 
-		if err := app.Run(); err != nil {
-			t.Fatal(err)
-		}
-
+		var c *C
 		switch {
-		case !aCalled:
+		case !app.Retrieve(&c):
 			t.FailNow()
-		case !bCalled:
+		case c != testC:
 			t.FailNow()
 		}
 	})
 
-	t.Run("no-runners", func(t *testing.T) {
+	t.Run("build-error", func(t *testing.T) {
 
-		app, err := chariot.New(chariot.With(
-			func() (*C, error) {
+		expected := errors.New("failure")
 
-				return new(C), nil
+		_, err := chariot.New(chariot.With(
+			func() *C {
+
+				return new(C)
 			},
-			func(*C) D {
+			func(*C) (*D, error) {
 
-				return D{}
+				return nil, expected
+			},
+			func(*D) *F {
+
+				return new(F)
 			},
 		))
-		if err != nil {
+
+		var buildErr *chariot.BuildError
+		switch {
+		case !errors.As(err, &buildErr):
+			t.Fatal(err)
+		case !errors.Is(err, expected):
+			t.Fatal(err)
+		case len(buildErr.Constructed) != 1 || buildErr.Constructed[0] != reflect.TypeOf(new(C)):
+			t.Fatal(buildErr.Constructed)
+		case len(buildErr.Pending) != 2:
+			t.Fatal(buildErr.Pending)
+		}
+	})
+
+	t.Run("slow-init-warning", func(t *testing.T) {
+
+		var slow []string
+
+		app, err := chariot.New(
+			chariot.With(
+				func() *C {
+
+					time.Sleep(20 * time.Millisecond)
+
+					return new(C)
+				},
+				func() *D {
+
+					return new(D)
+				},
+			),
+			chariot.WithSlowInitWarning(10*time.Millisecond, func(name string, took time.Duration) {
+
+				if took < 10*time.Millisecond {
+					t.Error(took)
+				}
+				slow = append(slow, name)
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if len(slow) != 1 || !strings.Contains(slow[0], "TestNewApp") {
+			t.Fatal(slow)
+		}
+	})
+
+	t.Run("slow-init-warning-nil-handler", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.WithSlowInitWarning(time.Millisecond, nil))
+		if err == nil {
+			app.Shutdown()
+			t.FailNow()
+		}
+	})
+}
+
+func TestAppValid(t *testing.T) {
+
+	t.Run("initialized", func(t *testing.T) {
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if !app.Valid() {
+			t.FailNow()
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func(*B) *A {
+
+			return new(A)
+		}))
+		switch {
+		case err == nil:
+			t.FailNow()
+		case app.Valid():
+			t.FailNow()
+		}
+	})
+
+	t.Run("copy", func(t *testing.T) {
+
+		var shutdowns int
+
+		app, err := chariot.New(chariot.With(func() A {
+
+			var a A
+			a.mocks.Shutdown = func(context.Context) {
+
+				shutdowns++
+			}
+
+			return a
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ctx context.Context
+		if !app.Retrieve(&ctx) {
+			t.FailNow()
+		}
+
+		appCopy := app
+		appCopy.Shutdown()
+
+		switch {
+		case ctx.Err() == nil:
+			t.FailNow()
+		case shutdowns != 1:
+			t.Fatal(shutdowns)
+		}
+	})
+}
+
+func TestAppDependencies(t *testing.T) {
+
+	newApp := func(t *testing.T, funcOptions ...chariot.Option) chariot.App {
+
+		app, err := chariot.New(
+			append(
+				funcOptions,
+				chariot.With(
+					func(*B, context.Context) *A {
+
+						return new(A)
+					},
+					func() *B {
+
+						return new(B)
+					},
+				),
+			)...,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return app
+	}
+
+	t.Run("introspection", func(t *testing.T) {
+
+		app := newApp(t, chariot.WithIntrospection())
+		defer app.Shutdown()
+
+		dependencies, ok := app.Dependencies((**A)(nil))
+		switch {
+		case !ok:
+			t.FailNow()
+		case len(dependencies) != 2:
+			t.Fatal(dependencies)
+		case dependencies[0] != reflect.TypeOf((*B)(nil)):
+			t.Fatal(dependencies)
+		}
+	})
+
+	t.Run("released", func(t *testing.T) {
+
+		app := newApp(t)
+		defer app.Shutdown()
+
+		if _, ok := app.Dependencies((**A)(nil)); ok {
+			t.FailNow()
+		}
+	})
+}
+
+func TestAppRun(t *testing.T) {
+
+	t.Run("simple-case", func(t *testing.T) {
+
+		var aCalled, bCalled bool
+
+		app, err := chariot.New(
+			chariot.With(
+				func() A {
+
+					var a A
+					a.mocks.Run = func(context.Context) error {
+
+						aCalled = true
+
+						return nil
+					}
+
+					return a
+				},
+				func() *B {
+
+					var b B
+					b.mocks.Run = func(context.Context) error {
+
+						bCalled = true
+
+						return nil
+					}
+
+					return &b
+				},
+			),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case !aCalled:
+			t.FailNow()
+		case !bCalled:
+			t.FailNow()
+		}
+	})
+
+	t.Run("no-runners", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(
+			func() (*C, error) {
+
+				return new(C), nil
+			},
+			func(*C) D {
+
+				return D{}
+			},
+		))
+		if err != nil {
 			t.Fatal(err)
 		}
 		defer app.Shutdown()
@@ -766,6 +1295,138 @@ func TestAppRun(t *testing.T) {
 			t.FailNow()
 		}
 	})
+
+	t.Run("concurrent-retrieve", func(t *testing.T) {
+
+		var app chariot.App
+
+		retrieve := func(context.Context) error {
+
+			for i := 0; i < 100; i++ {
+				var c *C
+				if !app.Retrieve(&c) {
+					return errors.New("failed to retrieve")
+				}
+			}
+
+			return nil
+		}
+
+		app, err := chariot.New(
+			chariot.WithComponents(new(C)),
+			chariot.With(
+				func() A {
+
+					var a A
+					a.mocks.Run = retrieve
+
+					return a
+				},
+				func() B {
+
+					var b B
+					b.mocks.Run = retrieve
+
+					return b
+				},
+			),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		done := make(chan struct{})
+		go func() {
+
+			defer close(done)
+
+			var a A
+			app.Retrieve(&a)
+		}()
+
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		<-done
+	})
+
+	t.Run("run-twice", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.WithComponents(new(A)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Run(); !errors.Is(err, chariot.ErrAppFinished) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("concurrent-run", func(t *testing.T) {
+
+		started, release := make(chan struct{}), make(chan struct{})
+
+		app, err := chariot.New(chariot.With(func() A {
+
+			var a A
+			a.mocks.Run = func(context.Context) error {
+
+				close(started)
+				<-release
+
+				return nil
+			}
+
+			return a
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		runErr := make(chan error)
+		go func() {
+
+			runErr <- app.Run()
+		}()
+
+		<-started
+		if err := app.Run(); !errors.Is(err, chariot.ErrAppRunning) {
+			t.Fatal(err)
+		}
+		close(release)
+
+		if err := <-runErr; err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("named-error", func(t *testing.T) {
+
+		testErr := errors.New("test error")
+
+		app, err := chariot.New(chariot.WithComponents(namedRunner(func(context.Context) error {
+
+			return testErr
+		})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		err = app.Run()
+		switch {
+		case !errors.Is(err, testErr):
+			t.Fatal(err)
+		case !strings.Contains(err.Error(), "runner 'named'"):
+			t.Fatal(err)
+		}
+	})
 }
 
 func TestAppShutdown(t *testing.T) {
@@ -859,6 +1520,178 @@ func TestAppShutdown(t *testing.T) {
 			t.Fatal(orderData)
 		}
 	})
+
+	t.Run("idempotent", func(t *testing.T) {
+
+		var shutdowns int
+
+		app, err := chariot.New(chariot.With(func() A {
+
+			var a A
+			a.mocks.Shutdown = func(context.Context) {
+
+				shutdowns++
+			}
+
+			return a
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app.Shutdown()
+		app.Shutdown()
+
+		if shutdowns != 1 {
+			t.Fatal(shutdowns)
+		}
+	})
+
+	t.Run("use-after", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.WithComponents(new(A)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app.Shutdown()
+
+		var a *A
+		switch {
+		case app.Retrieve(&a):
+			t.FailNow()
+		case !errors.Is(app.Run(), chariot.ErrAppClosed):
+			t.FailNow()
+		}
+	})
+
+	t.Run("during-run", func(t *testing.T) {
+
+		var (
+			events  = make(chan string, 2)
+			started = make(chan struct{})
+		)
+
+		app, err := chariot.New(chariot.With(func() A {
+
+			var a A
+			a.mocks.Run = func(ctx context.Context) error {
+
+				close(started)
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond)
+				events <- "run"
+
+				return nil
+			}
+			a.mocks.Shutdown = func(context.Context) {
+
+				events <- "shutdown"
+			}
+
+			return a
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runErr := make(chan error)
+		go func() {
+
+			runErr <- app.Run()
+		}()
+
+		<-started
+		app.Shutdown()
+
+		if err := <-runErr; err != nil {
+			t.Fatal(err)
+		}
+		if first, second := <-events, <-events; first != "run" || second != "shutdown" {
+			t.Fatal(first, second)
+		}
+	})
+
+	t.Run("shutdowner-timeout", func(t *testing.T) {
+
+		var (
+			release   = make(chan struct{})
+			aShutdown bool
+			reported  error
+		)
+		defer close(release)
+
+		app, err := chariot.New(chariot.With(
+			func() A {
+
+				var a A
+				a.mocks.Shutdown = func(context.Context) {
+
+					aShutdown = true
+				}
+
+				return a
+			},
+			func(A) B {
+
+				var b B
+				b.mocks.Shutdown = func(context.Context) {
+
+					<-release
+				}
+
+				return b
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app.Shutdown(
+			chariot.WithShutdownerTimeout(10*time.Millisecond),
+			chariot.WithShutdownErrorHandler(func(_ context.Context, err error) {
+
+				reported = err
+			}),
+		)
+
+		switch {
+		case !aShutdown:
+			t.FailNow()
+		case !errors.Is(reported, chariot.ErrShutdownTimeout):
+			t.Fatal(reported)
+		}
+	})
+
+	t.Run("run-values", func(t *testing.T) {
+
+		key := new(struct{})
+
+		var values []interface{}
+
+		var a A
+		a.mocks.Shutdown = func(ctx context.Context) {
+
+			values = append(values, ctx.Value(key))
+		}
+
+		for _, funcOptions := range [][]chariot.ShutdownOption{nil, {chariot.WithRunValues()}} {
+			app, err := chariot.New(chariot.WithComponents(a))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.WithValue(context.Background(), key, key)
+			if err := app.Run(chariot.WithRunContext(ctx)); err != nil {
+				t.Fatal(err)
+			}
+			app.Shutdown(funcOptions...)
+		}
+
+		if len(values) != 2 || values[0] != nil || values[1] != key {
+			t.Fatal(values)
+		}
+	})
 }
 
 func (a A) Run(ctx context.Context) (_ error) {
@@ -895,6 +1728,21 @@ func (b B) Shutdown(ctx context.Context) {
 
 func (*F) Foo() {}
 
+func (*G) Run(context.Context) (_ error) {
+
+	return
+}
+
+func (r namedRunner) Run(ctx context.Context) error {
+
+	return r(ctx)
+}
+
+func (namedRunner) Name() string {
+
+	return "named"
+}
+
 func (Error) Error() (_ string) {
 
 	return
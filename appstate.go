@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import "fmt"
+
+// AppState is a state of an app in the course of its lifecycle.
+type AppState int
+
+const (
+	// AppInitializing means the app's initializers are being invoked.
+	AppInitializing AppState = iota
+	// AppReady means the app is initialized and hasn't been run yet.
+	AppReady
+	// AppRunning means the app's runners are running.
+	AppRunning
+	// AppDraining means the app is being shut down.
+	AppDraining
+	// AppStopped means the app's runners have exited, or the app has been shut down.
+	AppStopped
+	// AppFailed means either the app has failed to initialize, or its run has returned an error
+	// other than a cancelled context.
+	// The state persists through the app's shutdown.
+	AppFailed
+)
+
+// State reports the state of the app.
+func (a App) State() AppState {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.appState
+}
+
+// StateChanged returns a channel that's closed once the state of the app changes. Call it again
+// after that to wait for the next change.
+func (a App) StateChanged() <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stateChanged == nil {
+		a.stateChanged = make(chan struct{})
+	}
+
+	return a.stateChanged
+}
+
+// String returns the name of the state.
+func (s AppState) String() string {
+	switch s {
+	case AppInitializing:
+		return "initializing"
+	case AppReady:
+		return "ready"
+	case AppRunning:
+		return "running"
+	case AppDraining:
+		return "draining"
+	case AppStopped:
+		return "stopped"
+	case AppFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("AppState(%d)", int(s))
+	}
+}
+
+// setState transitions the app to the state notifying the subscribers. It must be called with the
+// app's mutex held.
+func (a App) setState(state AppState) {
+	if state == AppFailed {
+		a.failed = true
+	}
+	if state == a.appState {
+		return
+	}
+
+	a.appState = state
+	a.crashes.record("app %s", state)
+	if a.stateChanged != nil {
+		close(a.stateChanged)
+		a.stateChanged = nil
+	}
+}
@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestAppState(t *testing.T) {
+
+	t.Run("lifecycle", func(t *testing.T) {
+
+		running := make(chan struct{})
+
+		var a A
+		a.mocks.Run = func(ctx context.Context) error {
+
+			close(running)
+			<-ctx.Done()
+
+			return nil
+		}
+
+		app, err := chariot.New(chariot.WithComponents(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state := app.State(); state != chariot.AppReady {
+			t.Fatal(state)
+		}
+
+		changed := app.StateChanged()
+		exited := make(chan error)
+		go func() {
+
+			exited <- app.Run()
+		}()
+
+		<-running
+		select {
+		case <-changed:
+		default:
+			t.FailNow()
+		}
+		if state := app.State(); state != chariot.AppRunning {
+			t.Fatal(state)
+		}
+
+		app.Shutdown()
+		if err := <-exited; err != nil {
+			t.Fatal(err)
+		}
+		if state := app.State(); state != chariot.AppStopped {
+			t.Fatal(state)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		var a A
+		a.mocks.Run = func(context.Context) error {
+
+			return errors.New("failure")
+		}
+
+		app, err := chariot.New(chariot.WithComponents(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.Run(); err == nil {
+			t.FailNow()
+		}
+		if state := app.State(); state != chariot.AppFailed {
+			t.Fatal(state)
+		}
+
+		app.Shutdown()
+		if state := app.State(); state != chariot.AppFailed {
+			t.Fatal(state)
+		}
+	})
+}
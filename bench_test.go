@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func BenchmarkNew(b *testing.B) {
+
+	for _, size := range [...]int{
+		10, 100, 1000, 5000,
+	} {
+		initializers := chain(size)
+
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				app, err := chariot.New(chariot.With(initializers...))
+				if err != nil {
+					b.Fatal(err)
+				}
+				app.Shutdown()
+			}
+		})
+	}
+}
+
+func BenchmarkPlanBuild(b *testing.B) {
+
+	for _, size := range [...]int{
+		10, 100, 1000, 5000,
+	} {
+		plan, err := chariot.NewPlan(chariot.With(chain(size)...))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				app, err := plan.Build(context.Background())
+				if err != nil {
+					b.Fatal(err)
+				}
+				app.Shutdown()
+			}
+		})
+	}
+}
+
+func BenchmarkAppRun(b *testing.B) {
+
+	for name, components := range map[string][]interface{}{
+		"single":   {A{}},
+		"multiple": {A{}, B{}},
+	} {
+		components := components
+
+		b.Run(name, func(b *testing.B) {
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				app, err := chariot.New(chariot.WithComponents(components...))
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := app.Run(); err != nil {
+					b.Fatal(err)
+				}
+				app.Shutdown()
+			}
+		})
+	}
+}
+
+// chain makes constructors of distinct types each depending on the two preceding ones, so the
+// deepest dependency path spans the whole graph.
+func chain(size int) []interface{} {
+
+	types := make([]reflect.Type, size)
+	for i := range types {
+		types[i] = reflect.StructOf([]reflect.StructField{
+			{
+				Name: fmt.Sprintf("F%d", i),
+				Type: reflect.TypeOf(struct{}{}),
+			},
+		})
+	}
+
+	initializers := make([]interface{}, size)
+	for i := range initializers {
+		var ins []reflect.Type
+		for j := i - 2; j < i; j++ {
+			if j >= 0 {
+				ins = append(ins, types[j])
+			}
+		}
+
+		out := types[i]
+		initializers[i] = reflect.MakeFunc(
+			reflect.FuncOf(ins, []reflect.Type{out}, false),
+			func([]reflect.Value) []reflect.Value {
+
+				return []reflect.Value{
+					reflect.Zero(out),
+				}
+			},
+		).Interface()
+	}
+
+	return initializers
+}
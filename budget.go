@@ -0,0 +1,210 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"runtime/metrics"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runnerLabel is the profiler label the goroutines of a runner are marked with once budgets are
+// monitored.
+const runnerLabel = "chariot_runner"
+
+type (
+	// Budget is a soft budget of the resources a runner, or the process as a whole, uses. Exceeding
+	// it merely triggers a warning (see the WithBudgetHandler option). A zero bound means none.
+	Budget struct {
+		// Goroutines bounds the goroutines of the runner: its own one and the ones started from it,
+		// directly or not. The goroutines are told apart by a profiler label.
+		Goroutines int
+		// Memory bounds the bytes of memory the runner reports to use (see the MemoryReporter
+		// interface), or the bytes occupied by the heap objects of the process.
+		Memory uint64
+	}
+
+	// MemoryReporter stands for a Runner-conformant component reporting the bytes of memory it
+	// uses, e.g. the size of its in-memory queue, for its memory budget to be monitored.
+	MemoryReporter interface {
+		MemoryUsage() uint64
+	}
+
+	// BudgetExceeded describes a budget exceeded by a runner or the process.
+	BudgetExceeded struct {
+		// Runner is the name of the runner, or empty for the process as a whole.
+		Runner string
+		// Resource is the resource exceeding the budget, either "goroutines" or "memory".
+		Resource string
+		// Usage is the usage of the resource.
+		Usage uint64
+		// Budget is the bound the usage exceeds.
+		Budget uint64
+	}
+
+	// budgetPolicy controls the way budgets are monitored.
+	budgetPolicy struct {
+		interval time.Duration
+		handler  func(BudgetExceeded)
+	}
+)
+
+// WithBudget attaches a soft budget to the runner of the type, e.g. an early warning for a runaway
+// consumer sharing a process with others. A nil type attaches the budget to the process as a
+// whole, measured with runtime/metrics. Budgets are monitored only with the WithBudgetHandler
+// option provided.
+func WithBudget(runnerType reflect.Type, budget Budget) RunOption {
+	return func(options *options) {
+		if options.budgets == nil {
+			options.budgets = make(map[reflect.Type]Budget)
+		}
+		options.budgets[runnerType] = budget
+	}
+}
+
+// WithBudgetHandler makes a run measure the usages against the budgets at the interval and invoke
+// the handler once a budget gets exceeded, and again only once the usage has fallen back within the
+// budget and exceeded it anew.
+func WithBudgetHandler(interval time.Duration, handler func(BudgetExceeded)) RunOption {
+	return func(options *options) {
+		options.budgetPolicy = &budgetPolicy{
+			interval: interval,
+			handler:  handler,
+		}
+	}
+}
+
+// monitored reports whether the budgets of a run are monitored.
+func (o options) monitored() bool {
+	return o.budgetPolicy != nil && o.budgetPolicy.interval > 0 && len(o.budgets) != 0
+}
+
+// monitorBudgets measures the usages against the budgets till the context is done.
+func monitorBudgets(ctx context.Context, runners []*managedRunner, options options) {
+	ticker := time.NewTicker(options.budgetPolicy.interval)
+	defer ticker.Stop()
+
+	exceeded := make(map[string]bool)
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		for _, usage := range measureBudgets(runners, options.budgets) {
+			key := usage.Runner + "/" + usage.Resource
+			if usage.Usage > usage.Budget && !exceeded[key] {
+				options.budgetPolicy.handler(usage)
+			}
+			exceeded[key] = usage.Usage > usage.Budget
+		}
+	}
+}
+
+// measureBudgets reports the usages of the resources bounded by the budgets, exceeded or not.
+func measureBudgets(runners []*managedRunner, budgets map[reflect.Type]Budget) []BudgetExceeded {
+	var (
+		usages     []BudgetExceeded
+		goroutines map[string]uint64
+	)
+	for _, runner := range runners {
+		budget, ok := budgets[runner.componentType]
+		if !ok || runner.componentType == nil {
+			continue
+		}
+
+		if budget.Goroutines > 0 {
+			if goroutines == nil {
+				goroutines = labeledGoroutines()
+			}
+			usages = append(usages, BudgetExceeded{
+				Runner:   runner.name(),
+				Resource: "goroutines",
+				Usage:    goroutines[runner.name()],
+				Budget:   uint64(budget.Goroutines),
+			})
+		}
+		if reporter, ok := runner.Runner.(MemoryReporter); ok && budget.Memory > 0 {
+			usages = append(usages, BudgetExceeded{
+				Runner:   runner.name(),
+				Resource: "memory",
+				Usage:    reporter.MemoryUsage(),
+				Budget:   budget.Memory,
+			})
+		}
+	}
+
+	if budget, ok := budgets[nil]; ok {
+		samples := []metrics.Sample{
+			{Name: "/sched/goroutines:goroutines"},
+			{Name: "/memory/classes/heap/objects:bytes"},
+		}
+		metrics.Read(samples)
+		for i, bound := range []uint64{uint64(budget.Goroutines), budget.Memory} {
+			if bound == 0 || samples[i].Value.Kind() != metrics.KindUint64 {
+				continue
+			}
+			usages = append(usages, BudgetExceeded{
+				Resource: [...]string{"goroutines", "memory"}[i],
+				Usage:    samples[i].Value.Uint64(),
+				Budget:   bound,
+			})
+		}
+	}
+
+	return usages
+}
+
+// labeledGoroutines counts the goroutines of the process by the runners they're labeled with.
+func labeledGoroutines() map[string]uint64 {
+	var profile bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&profile, 1)
+
+	counts := make(map[string]uint64)
+	var count uint64
+	for _, line := range strings.Split(profile.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && fields[1] == "@" {
+			count, _ = strconv.ParseUint(fields[0], 10, 64)
+
+			continue
+		}
+
+		raw := strings.TrimPrefix(line, "# labels: ")
+		if raw == line {
+			continue
+		}
+		var labels map[string]string
+		if err := json.Unmarshal([]byte(raw), &labels); err == nil && labels[runnerLabel] != "" {
+			counts[labels[runnerLabel]] += count
+		}
+	}
+
+	return counts
+}
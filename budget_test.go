@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+type hungry struct{}
+
+func (hungry) Run(ctx context.Context) error {
+
+	for i := 0; i < 3; i++ {
+		go func() {
+
+			<-ctx.Done()
+		}()
+	}
+	<-ctx.Done()
+
+	return nil
+}
+
+func (hungry) MemoryUsage() uint64 {
+
+	return 2048
+}
+
+func TestWithBudget(t *testing.T) {
+
+	app, err := chariot.New(chariot.With(func() hungry {
+
+		return hungry{}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	exceeded := make(chan chariot.BudgetExceeded, 8)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ran := make(chan error, 1)
+	go func() {
+
+		ran <- app.Run(
+			chariot.WithRunContext(ctx),
+			chariot.WithBudget(reflect.TypeOf(hungry{}), chariot.Budget{Goroutines: 2, Memory: 1024}),
+			chariot.WithBudget(nil, chariot.Budget{Goroutines: 1 << 20}),
+			chariot.WithBudgetHandler(time.Millisecond, func(budget chariot.BudgetExceeded) {
+
+				exceeded <- budget
+			}),
+		)
+	}()
+
+	resources := make(map[string]chariot.BudgetExceeded)
+	for len(resources) < 2 {
+		select {
+		case budget := <-exceeded:
+			resources[budget.Resource] = budget
+		case <-time.After(time.Second):
+			t.Fatal(resources)
+		}
+	}
+	cancel()
+	if err := <-ran; err != nil {
+		t.Fatal(err)
+	}
+
+	if budget := resources["goroutines"]; budget.Usage != 4 || budget.Runner == "" {
+		t.Fatal(budget)
+	}
+	if budget := resources["memory"]; budget.Usage != 2048 || budget.Budget != 1024 {
+		t.Fatal(budget)
+	}
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Provider is a fluent alternative to both annotating a constructor and relying on the positional
+// conventions of its returned values, e.g. that an error is the last one. A provider is passed to
+// the With option as is, and may be annotated further by the functions of the package, e.g. Lazy.
+//
+//	chariot.With(
+//		chariot.Provide(NewStore).
+//			As(reflect.TypeOf((*Storage)(nil)).Elem()).
+//			Named("primary storage").
+//			OnShutdown(func(ctx context.Context, storage Storage) { storage.Flush(ctx) }),
+//	)
+type Provider struct {
+	annotation annotation
+}
+
+// Provide starts building a provider out of a constructor.
+func Provide(constructor interface{}) *Provider {
+	return &Provider{annotation: annotationOf(constructor)}
+}
+
+// As provides the component of the constructor as the interface rather than as the type the
+// constructor returns. The constructor must return exactly one component, which must implement the
+// interface.
+func (p *Provider) As(iface reflect.Type) *Provider {
+	p.annotation.as = iface
+
+	return p
+}
+
+// Named names the constructor in errors and other reports (see the Describe function).
+func (p *Provider) Named(name string) *Provider {
+	p.annotation.description = name
+
+	return p
+}
+
+// NoError tells that the constructor doesn't return an error (see the NoError function).
+func (p *Provider) NoError() *Provider {
+	p.annotation.errorAt = noError
+
+	return p
+}
+
+// ErrorAt tells the position of the error among the values the constructor returns (see the
+// ErrorAt function).
+func (p *Provider) ErrorAt(position int) *Provider {
+	p.annotation.errorAt = errorPosition(position)
+
+	return p
+}
+
+// OnShutdown provides a hook invoked with a component of the constructor once the app is shut
+// down, as if the component was a Shutdowner. The hook is a function taking a context and the
+// component, e.g. func(context.Context, *sql.DB), and returning nothing.
+func (p *Provider) OnShutdown(hook interface{}) *Provider {
+	p.annotation.onShutdown = hook
+
+	return p
+}
+
+// provided adjusts the signature of the constructor to what the annotation provides, validating
+// the annotation along the way.
+func (a annotation) provided(analysed *signature) (*signature, error) {
+	if a.as != nil {
+		if a.as.Kind() != reflect.Interface {
+			return nil, fmt.Errorf("is provided as '%s', which isn't an interface", a.as)
+		}
+		if len(analysed.components) != 1 {
+			return nil, fmt.Errorf(
+				"is provided as '%s' but returns %d components, expected 1",
+				a.as,
+				len(analysed.components),
+			)
+		}
+		if !analysed.components[0].Implements(a.as) {
+			return nil, fmt.Errorf(
+				"is provided as '%s', which '%s' doesn't implement",
+				a.as,
+				analysed.components[0],
+			)
+		}
+
+		adjusted := *analysed
+		adjusted.components = []reflect.Type{a.as}
+		analysed = &adjusted
+	}
+
+	if a.onShutdown == nil {
+		return analysed, nil
+	}
+
+	hook := reflect.TypeOf(a.onShutdown)
+	if hook.Kind() != reflect.Func || hook.NumIn() != 2 || hook.NumOut() != 0 ||
+		hook.In(0) != ctxType {
+		return nil, fmt.Errorf(
+			"has a shutdown hook of type '%s', expected func(context.Context, <component>)",
+			hook,
+		)
+	}
+	for _, componentType := range analysed.components {
+		if componentType == hook.In(1) {
+			return analysed, nil
+		}
+	}
+
+	return nil, fmt.Errorf("has a shutdown hook taking '%s', which it doesn't return", hook.In(1))
+}
+
+// shutdownHook adapts the shutdown hook of a constructor to the component, if the hook takes it.
+func (n *node) shutdownHook(componentType reflect.Type, out reflect.Value) (Shutdowner, bool) {
+	if !n.onShutdown.IsValid() || n.onShutdown.Type().In(1) != componentType {
+		return nil, false
+	}
+
+	return FuncShutdowner(func(ctx context.Context) {
+		n.onShutdown.Call([]reflect.Value{reflect.ValueOf(&ctx).Elem(), out})
+	}), true
+}
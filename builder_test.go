@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestProvide(t *testing.T) {
+
+	eType := reflect.TypeOf((*E)(nil)).Elem()
+
+	t.Run("as", func(t *testing.T) {
+
+		var shutdown E
+		app, err := chariot.New(chariot.With(
+			chariot.Provide(func() (*F, error) {
+
+				return new(F), nil
+			}).As(eType).Named("foo").OnShutdown(func(_ context.Context, e E) {
+
+				shutdown = e
+			}),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var e E
+		if !app.Retrieve(&e) {
+			t.FailNow()
+		}
+		if _, ok := e.(*F); !ok {
+			t.Fatal(e)
+		}
+		var f *F
+		if app.Retrieve(&f) {
+			t.FailNow()
+		}
+		if description, _ := app.Description(&e); description != "foo" {
+			t.Fatal(description)
+		}
+
+		app.Shutdown()
+		if shutdown != e {
+			t.Fatal(shutdown)
+		}
+	})
+
+	t.Run("no-error", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(chariot.Provide(func() (*C, *Error) {
+
+			return new(C), new(Error)
+		}).NoError()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var e *Error
+		if !app.Retrieve(&e) {
+			t.FailNow()
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		newC := func() *C {
+
+			return new(C)
+		}
+		newF := func() *F {
+
+			return new(F)
+		}
+		newFC := func() (*F, *C) {
+
+			return new(F), new(C)
+		}
+		shutdownD := func(context.Context, *D) {}
+
+		for _, provider := range []*chariot.Provider{
+			chariot.Provide(newC).As(eType),
+			chariot.Provide(newFC).As(eType),
+			chariot.Provide(newF).As(reflect.TypeOf(new(F))),
+			chariot.Provide(newC).OnShutdown(func(*C) {}),
+			chariot.Provide(newC).OnShutdown(shutdownD),
+		} {
+			_, err := chariot.New(chariot.With(provider))
+			if err == nil || !strings.Contains(err.Error(), "initializer") {
+				t.Fatal(err)
+			}
+		}
+	})
+}
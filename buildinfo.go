@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// BuildInfo describes the binary an app is running within. An app is prepackaged with the
+// component so servers, loggers and the like can report exactly what is running. Fields are left
+// zero when the corresponding piece of information isn't embedded into the binary, e.g. VCS data
+// is only available when built within a repository and with -buildvcs enabled.
+type BuildInfo struct {
+	// GoVersion is the version of the Go toolchain that built the binary.
+	GoVersion string
+	// Path is the path of the main package.
+	Path string
+	// Version is the version of the main module.
+	Version string
+	// Revision is the VCS revision the binary was built from.
+	Revision string
+	// Time is the time of the VCS revision.
+	Time time.Time
+	// Modified reports whether the working tree had local modifications.
+	Modified bool
+}
+
+var (
+	buildInfoType = reflect.TypeOf(BuildInfo{})
+
+	buildInfo     BuildInfo
+	buildInfoOnce sync.Once
+)
+
+func readBuildInfo() BuildInfo {
+	buildInfoOnce.Do(func() {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+
+		buildInfo = BuildInfo{
+			GoVersion: info.GoVersion,
+			Path:      info.Path,
+			Version:   info.Main.Version,
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				buildInfo.Revision = setting.Value
+			case "vcs.time":
+				buildInfo.Time, _ = time.Parse(time.RFC3339, setting.Value)
+			case "vcs.modified":
+				buildInfo.Modified = setting.Value == "true"
+			}
+		}
+	})
+
+	return buildInfo
+}
+
+func (a App) setBuildInfoComponent() {
+	a.components[buildInfoType] = &component{
+		value: reflect.ValueOf(readBuildInfo()),
+	}
+}
@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// RetrieveByName retrieves a component by the name of its type, e.g. for debug tooling and admin
+// endpoints the type isn't available to at compile time. The name is either the one the type's
+// String method reports, e.g. "*http.Server", or the one qualified by the import path, e.g.
+// "*net/http.Server". Nothing is retrieved if the name is ambiguous, i.e. it matches types of
+// distinct packages, or under the same conditions the Retrieve method retrieves nothing.
+func (a App) RetrieveByName(name string) (interface{}, bool) {
+	if a.isClosed() {
+		return nil, false
+	}
+
+	var matched reflect.Type
+	for _, componentType := range a.componentTypes() {
+		if componentType.String() != name && qualifiedName(componentType) != name {
+			continue
+		}
+		if matched != nil && matched != componentType {
+			return nil, false
+		}
+		matched = componentType
+	}
+	if matched == nil {
+		return nil, false
+	}
+
+	atomic.AddInt64(&a.retrievals, 1)
+	defer a.traceRetrieval(matched, time.Now())
+	owner, component, _ := a.lookup(matched)
+	value, err := owner.valueFor(a.ctx, component, nil)
+	if err != nil {
+		return nil, false
+	}
+
+	return value.Interface(), true
+}
+
+// Components reports the types of both the app's own and the inherited components, ordered by
+// name. Nothing is reported if the app is closed.
+func (a App) Components() []reflect.Type {
+	if a.isClosed() {
+		return nil
+	}
+
+	types := a.componentTypes()
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].String() != types[j].String() {
+			return types[i].String() < types[j].String()
+		}
+
+		return qualifiedName(types[i]) < qualifiedName(types[j])
+	})
+
+	return types
+}
+
+// qualifiedName reports the name of the type qualified by the import path of its package, for
+// named types and pointers to them.
+func qualifiedName(t reflect.Type) string {
+	prefix := ""
+	for t.Kind() == reflect.Ptr && t.Name() == "" {
+		prefix += "*"
+		t = t.Elem()
+	}
+	if t.Name() == "" || t.PkgPath() == "" {
+		return prefix + t.String()
+	}
+
+	return prefix + t.PkgPath() + "." + t.Name()
+}
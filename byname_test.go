@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestAppRetrieveByName(t *testing.T) {
+
+	c := new(C)
+
+	app, err := chariot.New(chariot.WithComponents(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"*chariot_test.C", "*github.com/rwyyr/chariot_test.C"} {
+		component, ok := app.RetrieveByName(name)
+		if !ok || component != c {
+			t.Fatal(name, component)
+		}
+	}
+	if _, ok := app.RetrieveByName("*chariot_test.D"); ok {
+		t.FailNow()
+	}
+
+	app.Shutdown()
+	if _, ok := app.RetrieveByName("*chariot_test.C"); ok {
+		t.FailNow()
+	}
+}
+
+func TestAppComponents(t *testing.T) {
+
+	app, err := chariot.New(chariot.WithComponents(new(D), new(C)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scope, err := app.Scope(chariot.WithComponents(F{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer scope.Shutdown()
+
+	var names []string
+	listed := make(map[string]bool)
+	for _, componentType := range scope.Components() {
+		names = append(names, componentType.String())
+		listed[componentType.String()] = true
+	}
+	if !sort.StringsAreSorted(names) || !listed["*chariot_test.C"] || !listed["*chariot_test.D"] ||
+		!listed["chariot_test.F"] {
+		t.Fatal(names)
+	}
+
+	app.Shutdown()
+	if types := app.Components(); types != nil {
+		t.Fatal(types)
+	}
+}
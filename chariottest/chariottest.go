@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package chariottest supports testing apps. An app expensive to build, e.g. one connecting to a
+// database, is shared across the tests of a package: it's built once, shut down after the tests
+// have run, and each test opens an isolated scope of it, which may replace some of the components
+// and is shut down once the test completes:
+//
+//	var shared = chariottest.NewShared(chariot.With(newDB, newRepository))
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(shared.Main(m))
+//	}
+//
+//	func TestHandler(t *testing.T) {
+//		scope := shared.Scope(t, chariottest.Replace(fakeClock), chariot.With(newHandler))
+//		...
+//	}
+package chariottest
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+// Shared is an app shared across tests. It's built once first needed.
+type Shared struct {
+	options []chariot.Option
+	once    sync.Once
+	app     chariot.App
+	err     error
+}
+
+// NewShared makes a shared app out of the options.
+func NewShared(funcOptions ...chariot.Option) *Shared {
+	return &Shared{
+		options: funcOptions,
+	}
+}
+
+// Main builds the app, runs the tests, and shuts the app down. The exit code of the tests is
+// reported, unless the app fails to build, in which case the tests aren't run and 1 is reported. It
+// is to be called from TestMain.
+func (s *Shared) Main(m *testing.M) int {
+	app, err := s.App()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "building the shared app: %v\n", err)
+
+		return 1
+	}
+	defer app.Shutdown()
+
+	return m.Run()
+}
+
+// App reports the app, building it if it hasn't been yet.
+func (s *Shared) App() (chariot.App, error) {
+	s.once.Do(func() {
+		s.app, s.err = chariot.New(s.options...)
+	})
+
+	return s.app, s.err
+}
+
+// Scope opens a scope of the app initialized with the options, which is shut down once the test
+// completes. The test fails immediately if either the app or the scope fails to build.
+func (s *Shared) Scope(t testing.TB, funcOptions ...chariot.Option) chariot.App {
+	t.Helper()
+
+	app, err := s.App()
+	if err != nil {
+		t.Fatalf("building the shared app: %v", err)
+	}
+
+	scope, err := app.Scope(funcOptions...)
+	if err != nil {
+		t.Fatalf("opening a scope: %v", err)
+	}
+	t.Cleanup(func() {
+		scope.Shutdown()
+	})
+
+	return scope
+}
+
+// Replace provides components as values replacing the ones a scope inherits (see the
+// chariot.Override function). A nil value is ignored.
+func Replace(components ...interface{}) chariot.Option {
+	constructors := make([]interface{}, 0, len(components))
+	for _, component := range components {
+		if component == nil {
+			continue
+		}
+		value := reflect.ValueOf(component)
+		constructor := reflect.MakeFunc(
+			reflect.FuncOf(nil, []reflect.Type{value.Type()}, false),
+			func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{value}
+			},
+		)
+		constructors = append(constructors, chariot.Override(constructor.Interface()))
+	}
+
+	return chariot.With(constructors...)
+}
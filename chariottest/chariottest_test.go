@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariottest_test
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/chariottest"
+)
+
+type (
+	db struct {
+		name string
+	}
+
+	repository struct {
+		db *db
+	}
+)
+
+var (
+	builds int32
+	shared = chariottest.NewShared(chariot.With(func() *db {
+
+		atomic.AddInt32(&builds, 1)
+
+		return &db{name: "real"}
+	}))
+)
+
+func TestMain(m *testing.M) {
+
+	os.Exit(shared.Main(m))
+}
+
+func newRepository(db *db) *repository {
+
+	return &repository{db: db}
+}
+
+func TestShared(t *testing.T) {
+
+	t.Run("scope", func(t *testing.T) {
+
+		var repo *repository
+		if !shared.Scope(t, chariot.With(newRepository)).Retrieve(&repo) {
+			t.FailNow()
+		}
+		if repo.db.name != "real" {
+			t.Fatal(repo.db.name)
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+
+		scope := shared.Scope(t, chariottest.Replace(&db{name: "fake"}), chariot.With(newRepository))
+
+		var repo *repository
+		if !scope.Retrieve(&repo) {
+			t.FailNow()
+		}
+		if repo.db.name != "fake" {
+			t.Fatal(repo.db.name)
+		}
+
+		app, err := shared.App()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var original *db
+		if !app.Retrieve(&original) || original.name != "real" {
+			t.Fatal(original)
+		}
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+
+		var scope chariot.App
+		t.Run("test", func(t *testing.T) {
+
+			scope = shared.Scope(t)
+		})
+
+		var original *db
+		if scope.Retrieve(&original) {
+			t.FailNow()
+		}
+	})
+
+	if atomic.LoadInt32(&builds) != 1 {
+		t.Fatal(builds)
+	}
+}
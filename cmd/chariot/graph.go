@@ -0,0 +1,407 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const importPath = "github.com/rwyyr/chariot"
+
+// prepackaged lists the components every app provides on its own.
+var prepackaged = map[string]bool{
+	"context.Context":       true,
+	"chariot.BuildInfo":     true,
+	"*chariot.Signals":      true,
+	"*chariot.Spawner":      true,
+	"chariot.RunContext":    true,
+	"*chariot.StopToken":    true,
+	"*chariot.DebugToggles": true,
+	"chariot.Values":        true,
+	// A ComponentInfo is only provided to factories, which the analysis doesn't tell apart.
+	"chariot.ComponentInfo": true,
+}
+
+// annotations lists the functions of the package wrapping an initializer passed as the first
+// argument.
+var annotations = map[string]bool{
+	"ErrorAt":      true,
+	"NoError":      true,
+	"Lazy":         true,
+	"StartAfter":   true,
+	"Retry":        true,
+	"Scoped":       true,
+	"Factory":      true,
+	"Describe":     true,
+	"Override":     true,
+	"SnapshotSafe": true,
+	"Provide":      true,
+}
+
+// providerMethods lists the methods of a provider (see the Provide function) returning the
+// provider itself.
+var providerMethods = map[string]bool{
+	"As":         true,
+	"Named":      true,
+	"NoError":    true,
+	"ErrorAt":    true,
+	"OnShutdown": true,
+}
+
+type (
+	// graph is a set of providers found in a package.
+	graph struct {
+		providers  []*provider
+		unresolved []string
+	}
+
+	// provider is an initializer or a component found in a package.
+	provider struct {
+		name        string
+		position    token.Position
+		provides    []string
+		requires    []string
+		description string
+	}
+
+	// dependency is a component a provider requires that no provider provides.
+	dependency struct {
+		componentType string
+		provider      *provider
+	}
+)
+
+// analyse parses the Go files of the directory, except tests, and collects the providers passed
+// to the package's options.
+func analyse(dir string) (*graph, error) {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var graph graph
+	for _, pkg := range packages {
+		funcs := make(map[string]*ast.FuncDecl)
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+					funcs[funcDecl.Name.Name] = funcDecl
+				}
+			}
+		}
+
+		for _, file := range pkg.Files {
+			name, ok := importName(file)
+			if !ok {
+				continue
+			}
+			graph.collect(fset, file, name, funcs)
+		}
+	}
+
+	return &graph, nil
+}
+
+// importName reports the name the package is imported under in the file.
+func importName(file *ast.File) (string, bool) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != importPath {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name, true
+		}
+
+		return "chariot", true
+	}
+
+	return "", false
+}
+
+func (g *graph) collect(
+	fset *token.FileSet,
+	file *ast.File,
+	name string,
+	funcs map[string]*ast.FuncDecl,
+) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		switch selectorOf(call.Fun, name) {
+		case "With":
+			for _, arg := range call.Args {
+				g.collectInitializer(fset, unannotate(arg, name), describedAs(arg, name), funcs)
+			}
+		case "WithComponents":
+			for _, arg := range call.Args {
+				g.collectComponent(fset, arg)
+			}
+		}
+
+		return true
+	})
+}
+
+func (g *graph) collectInitializer(
+	fset *token.FileSet,
+	expr ast.Expr,
+	description string,
+	funcs map[string]*ast.FuncDecl,
+) {
+	position := fset.Position(expr.Pos())
+
+	switch expr := expr.(type) {
+	case *ast.FuncLit:
+		name := fmt.Sprintf("func literal at line %d", position.Line)
+		g.add(name, position, description, expr.Type)
+
+		return
+	case *ast.Ident:
+		if funcDecl, ok := funcs[expr.Name]; ok {
+			g.add(expr.Name, fset.Position(funcDecl.Pos()), description, funcDecl.Type)
+
+			return
+		}
+	}
+
+	g.unresolved = append(g.unresolved, fmt.Sprintf("%s (%s)", types.ExprString(expr), position))
+}
+
+func (g *graph) collectComponent(fset *token.FileSet, expr ast.Expr) {
+	position := fset.Position(expr.Pos())
+
+	var componentType string
+	switch expr := expr.(type) {
+	case *ast.CompositeLit:
+		componentType = types.ExprString(expr.Type)
+	case *ast.UnaryExpr:
+		if literal, ok := expr.X.(*ast.CompositeLit); ok && expr.Op == token.AND {
+			componentType = "*" + types.ExprString(literal.Type)
+		}
+	case *ast.CallExpr:
+		if ident, ok := expr.Fun.(*ast.Ident); ok && ident.Name == "new" && len(expr.Args) == 1 {
+			componentType = "*" + types.ExprString(expr.Args[0])
+		}
+	}
+	if componentType == "" {
+		g.unresolved = append(g.unresolved, fmt.Sprintf("%s (%s)", types.ExprString(expr), position))
+
+		return
+	}
+
+	g.providers = append(g.providers, &provider{
+		name:     fmt.Sprintf("component at line %d", position.Line),
+		position: position,
+		provides: []string{componentType},
+	})
+}
+
+func (g *graph) add(
+	name string,
+	position token.Position,
+	description string,
+	funcType *ast.FuncType,
+) {
+	provider := provider{
+		name:        name,
+		position:    position,
+		requires:    fieldTypes(funcType.Params),
+		description: description,
+	}
+	for _, result := range fieldTypes(funcType.Results) {
+		if result != "error" {
+			provider.provides = append(provider.provides, result)
+		}
+	}
+
+	g.providers = append(g.providers, &provider)
+}
+
+// missing reports the components required but not provided.
+func (g *graph) missing() []dependency {
+	provided := make(map[string]bool)
+	for _, provider := range g.providers {
+		for _, componentType := range provider.provides {
+			provided[componentType] = true
+		}
+	}
+
+	var missing []dependency
+	for _, provider := range g.providers {
+		for _, componentType := range provider.requires {
+			if !provided[componentType] && !prepackaged[componentType] {
+				missing = append(missing, dependency{componentType, provider})
+			}
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		return missing[i].componentType < missing[j].componentType
+	})
+
+	return missing
+}
+
+func (g *graph) print(w io.Writer) {
+	for _, provider := range g.providers {
+		fmt.Fprintf(w, "%s (%s)\n", provider.name, provider.position)
+		if provider.description != "" {
+			fmt.Fprintf(w, "\tdescribed as %s\n", provider.description)
+		}
+		for _, componentType := range provider.provides {
+			fmt.Fprintf(w, "\tprovides %s\n", componentType)
+		}
+		for _, componentType := range provider.requires {
+			fmt.Fprintf(w, "\trequires %s\n", componentType)
+		}
+	}
+
+	if missing := g.missing(); len(missing) != 0 {
+		fmt.Fprintln(w, "missing:")
+		for _, dependency := range missing {
+			fmt.Fprintf(w, "\t%s required by %s\n", dependency.componentType, dependency.provider.name)
+		}
+	}
+
+	if len(g.unresolved) != 0 {
+		fmt.Fprintln(w, "unresolved:")
+		for _, unresolved := range g.unresolved {
+			fmt.Fprintf(w, "\t%s\n", unresolved)
+		}
+	}
+}
+
+// selectorOf reports the name selected from the package imported under the name, if any.
+func selectorOf(expr ast.Expr, name string) string {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if ident, ok := selector.X.(*ast.Ident); !ok || ident.Name != name {
+		return ""
+	}
+
+	return selector.Sel.Name
+}
+
+// unannotate strips annotations off an initializer.
+func unannotate(expr ast.Expr, name string) ast.Expr {
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return expr
+		}
+		if receiver, ok := providerOf(call); ok {
+			expr = receiver
+
+			continue
+		}
+		if !annotations[selectorOf(call.Fun, name)] || len(call.Args) == 0 {
+			return expr
+		}
+		expr = call.Args[0]
+	}
+}
+
+// describedAs reports the description an initializer is annotated with, if it's a string literal.
+func describedAs(expr ast.Expr, name string) string {
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return ""
+		}
+		if receiver, ok := providerOf(call); ok {
+			if call.Fun.(*ast.SelectorExpr).Sel.Name == "Named" && len(call.Args) == 1 {
+				return stringLiteral(call.Args[0])
+			}
+			expr = receiver
+
+			continue
+		}
+		if !annotations[selectorOf(call.Fun, name)] || len(call.Args) == 0 {
+			return ""
+		}
+		if selectorOf(call.Fun, name) == "Describe" && len(call.Args) == 2 {
+			return stringLiteral(call.Args[1])
+		}
+		expr = call.Args[0]
+	}
+}
+
+// providerOf reports the receiver of a call of a provider method, if the call is one.
+func providerOf(call *ast.CallExpr) (ast.Expr, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !providerMethods[selector.Sel.Name] {
+		return nil, false
+	}
+	if _, ok := selector.X.(*ast.CallExpr); !ok {
+		return nil, false
+	}
+
+	return selector.X, true
+}
+
+// stringLiteral reports the value of a string literal, if the expression is one.
+func stringLiteral(expr ast.Expr) string {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return ""
+	}
+	value, _ := strconv.Unquote(literal.Value)
+
+	return value
+}
+
+func fieldTypes(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+
+	var fieldTypes []string
+	for _, field := range fields.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			fieldTypes = append(fieldTypes, types.ExprString(field.Type))
+		}
+	}
+
+	return fieldTypes
+}
@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command chariot prints the would-be dependency graph of the apps a package initializes, along
+// with the components missing a provider, without running the program. The analysis is static and
+// syntactic: initializers passed to chariot.With are resolved when they're function literals or
+// functions declared in the package, and components passed to chariot.WithComponents when they're
+// composite literals. Types are compared as written in the source.
+//
+// Usage:
+//
+//	chariot [dir]
+//
+// The directory defaults to the current one. The command exits with status 1 if any component is
+// missing a provider.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: chariot [dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	os.Exit(run(os.Stdout, dir))
+}
+
+func run(w io.Writer, dir string) int {
+	graph, err := analyse(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "chariot: %s\n", err)
+
+		return 2
+	}
+
+	graph.print(w)
+	if len(graph.missing()) != 0 {
+		return 1
+	}
+
+	return 0
+}
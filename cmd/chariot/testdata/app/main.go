@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rwyyr/chariot"
+)
+
+type (
+	Cache  struct{}
+	Config struct{}
+	DB     struct{}
+	Server struct{}
+)
+
+func newCache() *Cache {
+	return new(Cache)
+}
+
+func newServer(ctx context.Context, config *Config, db *DB) (*Server, error) {
+	return new(Server), nil
+}
+
+func main() {
+	app, err := chariot.New(
+		chariot.WithComponents(&Config{}),
+		chariot.With(chariot.Describe(chariot.Lazy(newServer), "public API server")),
+		chariot.With(chariot.Provide(newCache).NoError().Named("query cache")),
+	)
+	if err != nil {
+		panic(err)
+	}
+	defer app.Shutdown()
+}
@@ -0,0 +1,273 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package config loads config structs of an app from environment variables and reports the
+// variables the structs consume, so that operators can query a binary for its configuration
+// surface rather than rely on documentation drifting apart from the code. A field of a struct is
+// bound to a variable by the env tag, and may be given a default and a usage by the default and
+// the usage tags respectively:
+//
+//	type Config struct {
+//		Addr    string        `env:"ADDR" default:":8080" usage:"address to serve at"`
+//		Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+//		DB      struct {
+//			DSN string `env:"DB_DSN" usage:"data source name of the database"`
+//		}
+//	}
+//
+// Untagged struct fields are walked recursively. Strings, booleans, integers, floats, durations,
+// comma-separated string slices, and encoding.TextUnmarshaler implementations are supported.
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+type (
+	// Variable is an environment variable consumed by a config struct.
+	Variable struct {
+		// Name is the name of the variable.
+		Name string
+		// Type is the type of the field the variable is loaded into.
+		Type string
+		// Default is the value the field is loaded with when the variable is unset, if any.
+		Default string
+		// Usage describes the variable.
+		Usage string
+		// Config is the type of the config struct the variable belongs to.
+		Config reflect.Type
+		// Field is the path to the field the variable is loaded into, e.g. DB.DSN.
+		Field string
+	}
+
+	// Surface is the configuration surface of an app: the variables consumed by all its config
+	// structs.
+	Surface []Variable
+)
+
+var (
+	// ErrInvalidConfig is returned when a config isn't a pointer to a struct.
+	ErrInvalidConfig = errors.New("config must be a pointer to a struct")
+
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	errorType           = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Module provides the configs, pointers to structs, to an app as components of their types, each
+// loaded from the environment once constructed, along with the Surface of all of them. A single
+// module is to be provided to an app.
+func Module(configs ...interface{}) chariot.Module {
+	constructors := make([]interface{}, 0, len(configs)+1)
+	for _, config := range configs {
+		config := config
+		constructorType := reflect.FuncOf(
+			nil,
+			[]reflect.Type{reflect.TypeOf(config), errorType},
+			false,
+		)
+		constructors = append(constructors, reflect.MakeFunc(
+			constructorType,
+			func([]reflect.Value) []reflect.Value {
+				err := Load(config)
+				out := reflect.Zero(errorType)
+				if err != nil {
+					out = reflect.ValueOf(&err).Elem()
+				}
+
+				return []reflect.Value{reflect.ValueOf(config), out}
+			},
+		).Interface())
+	}
+	constructors = append(constructors, func() (Surface, error) {
+		return Variables(configs...)
+	})
+
+	return chariot.With(constructors...)
+}
+
+// Load loads the config, a pointer to a struct, from the environment. A variable that's unset
+// leaves the field with the default, if any, or intact otherwise, while a malformed one causes an
+// error.
+func Load(config interface{}) error {
+	return walk(config, func(variable Variable, field reflect.Value) error {
+		raw, ok := os.LookupEnv(variable.Name)
+		if !ok {
+			raw = variable.Default
+		}
+		if raw == "" {
+			return nil
+		}
+
+		if err := set(field, raw); err != nil {
+			return fmt.Errorf("environment variable %s: %w", variable.Name, err)
+		}
+
+		return nil
+	})
+}
+
+// Variables reports the variables the configs, pointers to structs, consume, in the order of the
+// configs and their fields.
+func Variables(configs ...interface{}) (Surface, error) {
+	var surface Surface
+	for _, config := range configs {
+		if err := walk(config, func(variable Variable, _ reflect.Value) error {
+			surface = append(surface, variable)
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return surface, nil
+}
+
+// String tabulates the surface one variable per line.
+func (s Surface) String() string {
+	var builder strings.Builder
+	w := tabwriter.NewWriter(&builder, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tDEFAULT\tUSAGE")
+	for _, variable := range s {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", variable.Name, variable.Type, variable.Default, variable.Usage)
+	}
+	w.Flush()
+
+	return builder.String()
+}
+
+// walk visits the fields of the config bound to variables.
+func walk(config interface{}, visit func(Variable, reflect.Value) error) error {
+	value := reflect.ValueOf(config)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: '%T'", ErrInvalidConfig, config)
+	}
+
+	return walkStruct(value.Type(), value.Elem(), "", visit)
+}
+
+func walkStruct(
+	configType reflect.Type,
+	value reflect.Value,
+	path string,
+	visit func(Variable, reflect.Value) error,
+) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, tagged := field.Tag.Lookup("env")
+		if !tagged {
+			if field.Type.Kind() == reflect.Struct && !isTextUnmarshaler(field.Type) {
+				err := walkStruct(configType, value.Field(i), path+field.Name+".", visit)
+				if err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if err := visit(Variable{
+			Name:    name,
+			Type:    field.Type.String(),
+			Default: field.Tag.Get("default"),
+			Usage:   field.Tag.Get("usage"),
+			Config:  configType,
+			Field:   path + field.Name,
+		}, value.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// set parses the raw value into the field.
+func set(field reflect.Value, raw string) error {
+	if isTextUnmarshaler(field.Type()) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch kind := field.Kind(); {
+	case kind == reflect.String:
+		field.SetString(raw)
+	case kind == reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(value)
+	case field.Type() == durationType:
+		value, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(value))
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		value, err := strconv.ParseInt(raw, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(value)
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(value)
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		value, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(value)
+	case kind == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		values := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			slice.Index(i).SetString(strings.TrimSpace(value))
+		}
+		field.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type '%s'", field.Type())
+	}
+
+	return nil
+}
+
+func isTextUnmarshaler(fieldType reflect.Type) bool {
+	return reflect.PtrTo(fieldType).Implements(textUnmarshalerType)
+}
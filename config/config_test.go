@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config_test
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/config"
+)
+
+type server struct {
+	Addr    string        `env:"TEST_ADDR" default:":8080" usage:"address to serve at"`
+	Timeout time.Duration `env:"TEST_TIMEOUT" default:"5s"`
+	Hosts   []string      `env:"TEST_HOSTS"`
+	IP      net.IP        `env:"TEST_IP"`
+	DB      struct {
+		Pool int `env:"TEST_DB_POOL" usage:"size of the pool"`
+	}
+	internal string
+}
+
+func TestModule(t *testing.T) {
+
+	t.Run("load", func(t *testing.T) {
+
+		t.Setenv("TEST_ADDR", ":9090")
+		t.Setenv("TEST_HOSTS", "a, b")
+		t.Setenv("TEST_IP", "10.0.0.1")
+		t.Setenv("TEST_DB_POOL", "4")
+
+		app, err := chariot.New(config.Module(new(server)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var loaded *server
+		switch {
+		case !app.Retrieve(&loaded):
+			t.FailNow()
+		case loaded.Addr != ":9090" || loaded.Timeout != 5*time.Second || loaded.DB.Pool != 4:
+			t.Fatal(loaded)
+		case len(loaded.Hosts) != 2 || loaded.Hosts[1] != "b" || loaded.IP.String() != "10.0.0.1":
+			t.Fatal(loaded)
+		}
+	})
+
+	t.Run("surface", func(t *testing.T) {
+
+		app, err := chariot.New(config.Module(new(server)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var surface config.Surface
+		if !app.Retrieve(&surface) {
+			t.FailNow()
+		}
+
+		var names []string
+		for _, variable := range surface {
+			names = append(names, variable.Name)
+		}
+		if strings.Join(names, ",") != "TEST_ADDR,TEST_TIMEOUT,TEST_HOSTS,TEST_IP,TEST_DB_POOL" {
+			t.Fatal(names)
+		}
+		if variable := surface[4]; variable.Field != "DB.Pool" || variable.Usage != "size of the pool" {
+			t.Fatal(variable)
+		}
+		if !strings.Contains(surface.String(), "TEST_ADDR") ||
+			!strings.Contains(surface.String(), "address to serve at") {
+			t.Fatal(surface.String())
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+
+		t.Setenv("TEST_TIMEOUT", "soon")
+
+		_, err := chariot.New(config.Module(new(server)))
+		if err == nil || !strings.Contains(err.Error(), "TEST_TIMEOUT") {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		if _, err := config.Variables(server{}); !errors.Is(err, config.ErrInvalidConfig) {
+			t.Fatal(err)
+		}
+	})
+}
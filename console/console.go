@@ -0,0 +1,366 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package console serves a line-based debug and admin console of a live app, over a unix socket by
+// default. Operators connect to the socket, e.g. with `nc -U`, and issue commands one per line:
+// list the components, along with the descriptions of their initializers, query the health of the
+// runners or the labels of the app, dump the dependency graph or the goroutines, retrieve a
+// component by name, toggle debug logging, suspend and resume the app, or drain and shut it down
+// without relying on signals. Every response is terminated by an empty line. The console is off
+// unless an app is run with the WithConsole option.
+package console
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+const help = `components           list the types of the components
+health               report the health of the runners and the retried initializers
+state                report the state of the app
+metadata             report the labels of the app
+graph                dump the dependencies of the components (requires WithIntrospection)
+get <type>           print the component of the type, e.g. "get *http.Server"
+goroutines           dump the stacks of the goroutines
+debug on|off         toggle debug logging (requires WithDebugToggle)
+toggle [name value]  list, get or set the debug toggles, e.g. "toggle chariot.verbose true"
+suspend              pause the runners conforming to chariot.Suspender
+resume               resume the suspended runners
+drain [timeout]      shut the app down letting the runners exit within the timeout, e.g. "drain 30s"
+shutdown [timeout]   shut the app down within the timeout, e.g. "shutdown 10s"
+quit                 close the connection`
+
+type (
+	// Option stands for an option of the console.
+	Option func(*options)
+
+	options struct {
+		debug func(bool)
+	}
+)
+
+// WithDebugToggle enables the debug command of the console, which invokes the toggle, e.g. to
+// switch the level of the app's logger.
+func WithDebugToggle(toggle func(enabled bool)) Option {
+	return func(options *options) {
+		options.debug = toggle
+	}
+}
+
+// WithConsole makes an app serve the console at the unix socket path while it runs. Errors of the
+// console are reported to the handler, if it isn't nil; an error of the console fails neither the
+// run nor the app.
+func WithConsole(path string, handler func(error), funcOptions ...Option) chariot.RunOption {
+	if handler == nil {
+		handler = func(error) {}
+	}
+	var serving sync.WaitGroup
+
+	return chariot.RunOption(chariot.WithOptions(
+		chariot.WithBeforeRun(func(ctx context.Context, app chariot.App) error {
+			listener, err := net.Listen("unix", path)
+			if err != nil {
+				handler(fmt.Errorf("console: %w", err))
+
+				return nil
+			}
+
+			serving.Add(1)
+			go func() {
+				defer serving.Done()
+
+				if err := Serve(ctx, app, listener, funcOptions...); err != nil {
+					handler(fmt.Errorf("console: %w", err))
+				}
+			}()
+
+			return nil
+		}),
+		chariot.WithAfterRun(func(context.Context, chariot.App, error) {
+			serving.Wait()
+		}),
+	))
+}
+
+// Serve serves the console of the app at the listener until the context is cancelled. The listener
+// and the connections are closed before the function returns.
+func Serve(
+	ctx context.Context,
+	app chariot.App,
+	listener net.Listener,
+	funcOptions ...Option,
+) error {
+	var options options
+	for _, option := range funcOptions {
+		option(&options)
+	}
+
+	var (
+		mu          sync.Mutex
+		connections = make(map[net.Conn]struct{})
+		handling    sync.WaitGroup
+	)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+		}
+		listener.Close()
+
+		mu.Lock()
+		defer mu.Unlock()
+		for conn := range connections {
+			conn.Close()
+		}
+	}()
+
+	defer handling.Wait()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return err
+		}
+
+		mu.Lock()
+		if ctx.Err() != nil {
+			mu.Unlock()
+			conn.Close()
+
+			return nil
+		}
+		connections[conn] = struct{}{}
+		mu.Unlock()
+
+		handling.Add(1)
+		go func() {
+			defer handling.Done()
+			defer func() {
+				mu.Lock()
+				defer mu.Unlock()
+
+				delete(connections, conn)
+				conn.Close()
+			}()
+
+			serve(app, conn, options)
+		}()
+	}
+}
+
+func serve(app chariot.App, conn io.ReadWriter, options options) {
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" {
+			return
+		}
+
+		writer := bufio.NewWriter(conn)
+		execute(app, writer, fields[0], strings.Join(fields[1:], " "), options)
+		fmt.Fprintln(writer)
+		if writer.Flush() != nil {
+			return
+		}
+	}
+}
+
+func execute(app chariot.App, w io.Writer, command, argument string, options options) {
+	switch command {
+	case "help":
+		fmt.Fprintln(w, help)
+	case "components":
+		for _, componentType := range app.Components() {
+			fmt.Fprintln(w, label(app, componentType))
+		}
+	case "health":
+		for _, health := range app.Health() {
+			fmt.Fprintf(w, "%s\t%s\trestarts=%d", health.Name, health.State, health.Restarts)
+			if health.Err != nil {
+				fmt.Fprintf(w, "\terror=%v", health.Err)
+			}
+			fmt.Fprintln(w)
+		}
+		for _, status := range app.Retrying() {
+			fmt.Fprintln(w, status)
+		}
+	case "state":
+		fmt.Fprintln(w, app.State())
+	case "metadata":
+		metadata := app.Metadata()
+		keys := make([]string, 0, len(metadata))
+		for key := range metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s=%s\n", key, metadata[key])
+		}
+	case "graph":
+		for _, componentType := range app.Components() {
+			dependencies, _ := app.Dependencies(reflect.New(componentType).Interface())
+			names := make([]string, 0, len(dependencies))
+			for _, dependency := range dependencies {
+				names = append(names, dependency.String())
+			}
+			if len(names) == 0 {
+				fmt.Fprintln(w, label(app, componentType))
+
+				continue
+			}
+			fmt.Fprintf(w, "%s <- %s\n", label(app, componentType), strings.Join(names, ", "))
+		}
+	case "get":
+		component, found := app.RetrieveByName(argument)
+		if !found {
+			fmt.Fprintf(w, "no component of the type '%s'\n", argument)
+
+			return
+		}
+		fmt.Fprintf(w, "%+v\n", component)
+	case "goroutines":
+		var dump bytes.Buffer
+		pprof.Lookup("goroutine").WriteTo(&dump, 2)
+		// Empty lines separating the stacks are dropped as they terminate responses.
+		for _, line := range strings.Split(dump.String(), "\n") {
+			if line != "" {
+				fmt.Fprintln(w, line)
+			}
+		}
+	case "debug":
+		if options.debug == nil {
+			fmt.Fprintln(w, "debug logging isn't configured")
+
+			return
+		}
+		switch argument {
+		case "on", "off":
+			options.debug(argument == "on")
+			fmt.Fprintf(w, "debug logging %s\n", argument)
+		default:
+			fmt.Fprintln(w, "usage: debug on|off")
+		}
+	case "toggle":
+		var toggles *chariot.DebugToggles
+		app.Retrieve(&toggles)
+		name, value := argument, ""
+		if i := strings.IndexByte(argument, ' '); i != -1 {
+			name, value = argument[:i], strings.TrimSpace(argument[i+1:])
+		}
+		switch {
+		case name == "":
+			for _, name := range toggles.Names() {
+				value, _ := toggles.Get(name)
+				fmt.Fprintf(w, "%s=%s\n", name, value)
+			}
+		case value == "":
+			value, ok := toggles.Get(name)
+			if !ok {
+				fmt.Fprintf(w, "unknown debug toggle '%s'\n", name)
+
+				return
+			}
+			fmt.Fprintf(w, "%s=%s\n", name, value)
+		default:
+			if err := toggles.Set(name, value); err != nil {
+				fmt.Fprintf(w, "error: %v\n", err)
+
+				return
+			}
+			fmt.Fprintf(w, "%s=%s\n", name, value)
+		}
+	case "suspend", "resume":
+		suspend, done := app.Suspend, "suspended"
+		if command == "resume" {
+			suspend, done = app.Resume, "resumed"
+		}
+		if err := suspend(context.Background()); err != nil {
+			fmt.Fprintf(w, "error: %v\n", strings.ReplaceAll(err.Error(), "\n", "; "))
+
+			return
+		}
+		fmt.Fprintln(w, done)
+	case "drain", "shutdown":
+		var funcOptions []chariot.ShutdownOption
+		if argument != "" {
+			timeout, err := time.ParseDuration(argument)
+			if err != nil || timeout <= 0 {
+				fmt.Fprintf(w, "invalid timeout '%s'\n", argument)
+
+				return
+			}
+			funcOptions = append(funcOptions, shutdownTimeout(command, timeout))
+		}
+		// The shutdown waits for the run to exit, which in turn waits for the console to close, so
+		// it's not to be waited for.
+		go app.Shutdown(funcOptions...)
+		fmt.Fprintln(w, "shutting down")
+	default:
+		fmt.Fprintf(w, "unknown command '%s'; type help\n", command)
+	}
+}
+
+// shutdownTimeout bounds either the wait for the runners to exit, for the drain command, or the
+// entire shutdown, for the shutdown one.
+func shutdownTimeout(command string, timeout time.Duration) chariot.ShutdownOption {
+	if command == "drain" {
+		return chariot.WithRunExitTimeout(timeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	// The shutdown isn't waited for, so the context is released once it expires.
+	time.AfterFunc(timeout, cancel)
+
+	return chariot.WithShutdownContext(ctx)
+}
+
+// label reports the type of a component along with the description of its initializer, if any.
+func label(app chariot.App, componentType reflect.Type) string {
+	description, ok := app.Description(reflect.New(componentType).Interface())
+	if !ok {
+		return componentType.String()
+	}
+
+	return fmt.Sprintf("%s (%s)", componentType, description)
+}
@@ -0,0 +1,236 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package console_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/console"
+)
+
+type config struct {
+	Name string
+}
+
+type server struct{}
+
+func newServer(*config) server {
+
+	return server{}
+}
+
+func (server) Run(ctx context.Context) error {
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestWithConsole(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "console")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.sock")
+
+	app, err := chariot.New(
+		chariot.WithComponents(&config{Name: "console"}),
+		chariot.With(chariot.Describe(newServer, "request server")),
+		chariot.WithIntrospection(),
+		chariot.WithMetadata(map[string]string{"service": "api", "env": "test"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	debug := make(chan bool, 2)
+	toggle := console.WithDebugToggle(func(enabled bool) {
+
+		debug <- enabled
+	})
+
+	ran := make(chan error, 1)
+	go func() {
+
+		ran <- app.Run(console.WithConsole(path, func(err error) {
+
+			t.Error(err)
+		}, toggle))
+	}()
+	<-app.Ready()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	execute := func(t *testing.T, command string) string {
+
+		if _, err := conn.Write([]byte(command + "\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		var response strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line == "\n" {
+				return response.String()
+			}
+			response.WriteString(line)
+		}
+	}
+
+	t.Run("components", func(t *testing.T) {
+
+		if response := execute(t, "components"); !strings.Contains(response, "*console_test.config\n") ||
+			!strings.Contains(response, "console_test.server (request server)\n") {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("state", func(t *testing.T) {
+
+		if response := execute(t, "state"); response != chariot.AppRunning.String()+"\n" {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+
+		if response := execute(t, "metadata"); response != "env=test\nservice=api\n" {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("health", func(t *testing.T) {
+
+		if response := execute(t, "health"); !strings.Contains(response, "console_test.server") {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("graph", func(t *testing.T) {
+
+		if response := execute(t, "graph"); !strings.Contains(response,
+			"console_test.server (request server) <- *console_test.config\n") {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+
+		if response := execute(t, "get *console_test.config"); response != "&{Name:console}\n" {
+			t.Fatal(response)
+		}
+		response := execute(t, "get *console_test.missing")
+		if !strings.HasPrefix(response, "no component") {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("goroutines", func(t *testing.T) {
+
+		if response := execute(t, "goroutines"); !strings.Contains(response, "goroutine ") {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("debug", func(t *testing.T) {
+
+		if response := execute(t, "debug on"); response != "debug logging on\n" || !<-debug {
+			t.Fatal(response)
+		}
+		if response := execute(t, "debug off"); response != "debug logging off\n" || <-debug {
+			t.Fatal(response)
+		}
+		if response := execute(t, "debug"); !strings.HasPrefix(response, "usage") {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("toggle", func(t *testing.T) {
+
+		if response := execute(t, "toggle chariot.verbose true"); response != "chariot.verbose=true\n" {
+			t.Fatal(response)
+		}
+		if response := execute(t, "toggle"); !strings.Contains(response, "chariot.verbose=true\n") {
+			t.Fatal(response)
+		}
+		if response := execute(t, "toggle chariot.missing"); !strings.HasPrefix(response, "unknown") {
+			t.Fatal(response)
+		}
+		execute(t, "toggle chariot.verbose false")
+	})
+
+	t.Run("suspend", func(t *testing.T) {
+
+		if response := execute(t, "suspend"); response != "suspended\n" || !app.Suspended() {
+			t.Fatal(response)
+		}
+		if response := execute(t, "resume"); response != "resumed\n" || app.Suspended() {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("invalid-timeout", func(t *testing.T) {
+
+		if response := execute(t, "shutdown soon"); response != "invalid timeout 'soon'\n" {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+
+		if response := execute(t, "unknown"); !strings.HasPrefix(response, "unknown command") {
+			t.Fatal(response)
+		}
+	})
+
+	t.Run("drain", func(t *testing.T) {
+
+		if response := execute(t, "drain 5s"); response != "shutting down\n" {
+			t.Fatal(response)
+		}
+		if err := <-ran; err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+	})
+}
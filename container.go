@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// Container is a restricted view of an app, safe to be handed to application code and plugins: it
+// retrieves components and invokes functions with them, whereas running the app and shutting it
+// down stay confined to the owner of the App, e.g. main.
+type Container interface {
+	Retrieve(ptr interface{}) bool
+	RetrieveCtx(ctx context.Context, ptr interface{}) error
+	Invoke(function interface{}) error
+}
+
+// container is the Container-conformant view of an app, which doesn't expose the app itself.
+type container struct {
+	app App
+}
+
+// Container makes a restricted view of the app.
+func (a App) Container() Container {
+	return container{app: a}
+}
+
+// Invoke invokes the function with the components it takes as arguments and returns the error the
+// function returns, if any. The function is to return either nothing or an error alone, as an init
+// does, and mustn't be variadic. An error is returned as well if the app has been shut down
+// (ErrAppClosed) or it lacks a component the function takes.
+func (a App) Invoke(function interface{}) error {
+	if a.isClosed() {
+		return ErrAppClosed
+	}
+
+	value := reflect.ValueOf(function)
+	if value.Kind() != reflect.Func {
+		return fmt.Errorf("'%v' isn't a function", function)
+	}
+
+	signature, err := signatureOf(value.Type(), autoErrorAt)
+	if err != nil {
+		return fmt.Errorf("function '%s' %w", funcName(value), err)
+	}
+	if len(signature.components) != 0 || signature.variadic != nil {
+		return fmt.Errorf("function '%s' either returns components or is variadic", funcName(value))
+	}
+
+	ins := make([]reflect.Value, 0, len(signature.dependencies))
+	for _, dependencyType := range signature.dependencies {
+		atomic.AddInt64(&a.retrievals, 1)
+		owner, component, found := a.lookup(dependencyType)
+		if !found {
+			return fmt.Errorf("%w '%s'", ErrMissingComponent, dependencyType)
+		}
+
+		in, err := owner.valueFor(a.ctx, component, nil)
+		if err != nil {
+			return err
+		}
+		ins = append(ins, in)
+	}
+
+	_, err = signature.split(value.Call(ins))
+
+	return err
+}
+
+// Retrieve retrieves a component the way the Retrieve method of App does.
+func (c container) Retrieve(ptr interface{}) bool {
+	return c.app.Retrieve(ptr)
+}
+
+// RetrieveCtx retrieves a component the way the RetrieveCtx method of App does.
+func (c container) RetrieveCtx(ctx context.Context, ptr interface{}) error {
+	return c.app.RetrieveCtx(ctx, ptr)
+}
+
+// Invoke invokes the function the way the Invoke method of App does.
+func (c container) Invoke(function interface{}) error {
+	return c.app.Invoke(function)
+}
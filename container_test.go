@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestAppContainer(t *testing.T) {
+
+	c := new(C)
+
+	app, err := chariot.New(chariot.WithComponents(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	container := app.Container()
+	if _, ok := container.(chariot.App); ok {
+		t.FailNow()
+	}
+
+	t.Run("retrieve", func(t *testing.T) {
+
+		var retrieved *C
+		if !container.Retrieve(&retrieved) || retrieved != c {
+			t.FailNow()
+		}
+		err := container.RetrieveCtx(context.Background(), new(*D))
+		if !errors.Is(err, chariot.ErrMissingComponent) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("invoke", func(t *testing.T) {
+
+		var invoked *C
+		if err := container.Invoke(func(_ context.Context, c *C) {
+
+			invoked = c
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if invoked != c {
+			t.FailNow()
+		}
+
+		testErr := errors.New("test")
+		if err := container.Invoke(func(*C) error {
+
+			return testErr
+		}); !errors.Is(err, testErr) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("invalid-function", func(t *testing.T) {
+
+		for _, function := range []interface{}{
+			nil,
+			c,
+			func() *D { return nil },
+			func(...*C) {},
+		} {
+			if err := container.Invoke(function); err == nil {
+				t.Fatal(function)
+			}
+		}
+		if err := container.Invoke(func(*D) {}); !errors.Is(err, chariot.ErrMissingComponent) {
+			t.Fatal(err)
+		}
+	})
+
+	app.Shutdown()
+	if err := container.Invoke(func() {}); !errors.Is(err, chariot.ErrAppClosed) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,193 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// retainedEvents is the number of the latest lifecycle events of an app retained for crash reports.
+const retainedEvents = 32
+
+// CrashInfo describes a panic recovered or a fatal failure of an app's lifecycle (see the
+// WithCrashReporter option).
+type CrashInfo struct {
+	// Component is the name of the initializer, the runner or the shutdowner that panicked, or
+	// empty for a failure of the app as a whole.
+	Component string
+	// Phase is the phase of the lifecycle the crash happened in: "init", "run" or "shutdown".
+	Phase string
+	// Recovered is the value recovered from the panic, or nil for a failure.
+	Recovered interface{}
+	// Err is the error the panic was turned into, or the one the app failed with.
+	Err error
+	// Stack is the stack of the goroutine that panicked, or nil for a failure.
+	Stack []byte
+	// Events are the latest lifecycle events of the app, the oldest first.
+	Events []LifecycleEvent
+}
+
+// LifecycleEvent is an event of an app's lifecycle, e.g. a runner having exited.
+type LifecycleEvent struct {
+	// Time is the time the event happened at.
+	Time time.Time
+	// Description describes the event.
+	Description string
+}
+
+// crashReporter reports crashes of an app along with the latest lifecycle events. A nil reporter
+// neither recovers panics nor records events.
+type crashReporter struct {
+	report func(context.Context, CrashInfo)
+
+	mu     sync.Mutex
+	events []LifecycleEvent
+	next   int
+}
+
+// WithCrashReporter makes an app report panics and fatal failures of its lifecycle to the reporter,
+// e.g. to integrate Sentry-like services. Panics of initializers, runners, sub-runners (see the
+// Spawner type) and shutdowners are recovered and turned into errors, which fail the initialization
+// or the run the way errors returned do. Failures to initialize and to run are reported as well.
+// The reporter is invoked synchronously and may be invoked concurrently.
+func WithCrashReporter(reporter func(context.Context, CrashInfo)) Option {
+	return func(options *options) {
+		options.crashReporter = reporter
+	}
+}
+
+func newCrashReporter(report func(context.Context, CrashInfo)) *crashReporter {
+	if report == nil {
+		return nil
+	}
+
+	return &crashReporter{report: report}
+}
+
+// record records a lifecycle event.
+func (c *crashReporter) record(format string, args ...interface{}) {
+	if c == nil {
+		return
+	}
+
+	event := LifecycleEvent{
+		Time:        time.Now(),
+		Description: fmt.Sprintf(format, args...),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.events) < retainedEvents {
+		c.events = append(c.events, event)
+
+		return
+	}
+	c.events[c.next] = event
+	c.next = (c.next + 1) % retainedEvents
+}
+
+// guard invokes the function recovering and reporting a panic, which is turned into an error.
+func (c *crashReporter) guard(
+	ctx context.Context,
+	phase string,
+	component func() string,
+	fn func() error,
+) (err error) {
+	if c == nil {
+		return fn()
+	}
+
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		name := component()
+		err = fmt.Errorf("panic: %v", recovered)
+		c.record("%s '%s' panicked: %v", phase, name, recovered)
+		c.report(ctx, CrashInfo{
+			Component: name,
+			Phase:     phase,
+			Recovered: recovered,
+			Err:       err,
+			Stack:     debug.Stack(),
+			Events:    c.latest(),
+		})
+	}()
+
+	return fn()
+}
+
+// fail reports a fatal failure of the phase.
+func (c *crashReporter) fail(ctx context.Context, phase string, err error) {
+	if c == nil {
+		return
+	}
+
+	c.record("%s failed: %v", phase, err)
+	c.report(ctx, CrashInfo{
+		Phase:  phase,
+		Err:    err,
+		Events: c.latest(),
+	})
+}
+
+// latest reports the retained events, the oldest first.
+func (c *crashReporter) latest() []LifecycleEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	events := make([]LifecycleEvent, 0, len(c.events))
+	events = append(events, c.events[c.next:]...)
+
+	return append(events, c.events[:c.next]...)
+}
+
+// callInitializer invokes the initializer recovering its panic if the app reports crashes.
+func (a App) callInitializer(constructor *node, ins []reflect.Value) ([]reflect.Value, error) {
+	var outs []reflect.Value
+	name := func() string {
+		return funcName(constructor.initializer)
+	}
+	err := a.crashes.guard(a.ctx, "init", name, func() (err error) {
+		outs, err = constructor.call(ins, a.retries)
+
+		return err
+	})
+	if a.crashes != nil {
+		if err != nil {
+			a.crashes.record("initializer '%s' failed: %v", name(), err)
+		} else {
+			a.crashes.record("initializer '%s' returned", name())
+		}
+	}
+
+	return outs, err
+}
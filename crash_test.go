@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+type crashes struct {
+	mu      sync.Mutex
+	reports []chariot.CrashInfo
+}
+
+func (c *crashes) report(_ context.Context, info chariot.CrashInfo) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reports = append(c.reports, info)
+}
+
+func TestWithCrashReporter(t *testing.T) {
+
+	t.Run("init", func(t *testing.T) {
+
+		var c crashes
+
+		_, err := chariot.New(chariot.With(func() *C {
+
+			panic("boom")
+		}), chariot.WithCrashReporter(c.report))
+		if err == nil || !strings.Contains(err.Error(), "panic: boom") {
+			t.Fatal(err)
+		}
+
+		if len(c.reports) != 2 {
+			t.Fatal(c.reports)
+		}
+		panicked, failed := c.reports[0], c.reports[1]
+		switch {
+		case panicked.Phase != "init" || panicked.Recovered != "boom" || len(panicked.Stack) == 0:
+			t.Fatal(panicked)
+		case !strings.Contains(panicked.Component, "TestWithCrashReporter"):
+			t.Fatal(panicked.Component)
+		case failed.Phase != "init" || failed.Recovered != nil || !errors.Is(failed.Err, err):
+			t.Fatal(failed)
+		}
+	})
+
+	t.Run("run", func(t *testing.T) {
+
+		var c crashes
+
+		app, err := chariot.New(chariot.WithComponents(namedRunner(func(context.Context) error {
+
+			panic("boom")
+		})), chariot.WithCrashReporter(c.report))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(); err == nil || !strings.Contains(err.Error(), "panic: boom") {
+			t.Fatal(err)
+		}
+
+		if len(c.reports) != 2 || c.reports[0].Component != "named" || c.reports[1].Phase != "run" {
+			t.Fatal(c.reports)
+		}
+
+		var descriptions []string
+		for _, event := range c.reports[0].Events {
+			descriptions = append(descriptions, event.Description)
+		}
+		if !strings.Contains(strings.Join(descriptions, "\n"), "runner 'named' running") {
+			t.Fatal(descriptions)
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+
+		var c crashes
+
+		a := new(A)
+		a.mocks.Shutdown = func(context.Context) {
+
+			panic("boom")
+		}
+
+		app, err := chariot.New(chariot.WithComponents(a), chariot.WithCrashReporter(c.report))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var reported error
+		app.Shutdown(chariot.WithShutdownErrorHandler(func(_ context.Context, err error) {
+
+			reported = err
+		}))
+
+		if reported == nil || len(c.reports) != 1 || c.reports[0].Phase != "shutdown" {
+			t.Fatal(reported, c.reports)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestDescribe(t *testing.T) {
+
+	t.Run("description", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(
+			chariot.Describe(chariot.Lazy(func() *C {
+
+				return new(C)
+			}), "primary pool"),
+			func() *D {
+
+				return new(D)
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if description, ok := app.Description((**C)(nil)); !ok || description != "primary pool" {
+			t.Fatal(description)
+		}
+		if _, ok := app.Description((**D)(nil)); ok {
+			t.FailNow()
+		}
+	})
+
+	t.Run("initializer-error", func(t *testing.T) {
+
+		testErr := errors.New("test")
+		_, err := chariot.New(chariot.With(chariot.Describe(func() (*C, error) {
+
+			return nil, testErr
+		}, "primary pool")))
+		if !errors.Is(err, testErr) || !strings.HasPrefix(err.Error(), "primary pool: ") {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("plan-error", func(t *testing.T) {
+
+		_, err := chariot.New(chariot.With(chariot.Describe(chariot.Factory(func() (*C, *D) {
+
+			return new(C), new(D)
+		}), "primary pool")))
+		if err == nil || !strings.Contains(err.Error(), "(primary pool)") {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package discovery registers apps with a service discovery backend. An app is registered once all
+// its runners are ready, and deregistered as soon as it starts draining (see the Draining method of
+// chariot.App), so that no traffic is routed to it while it finishes the work in flight. Backends,
+// e.g. Consul or DNS-SD, are plugged in by implementing the Registry interface.
+package discovery
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rwyyr/chariot"
+)
+
+type (
+	// Registry stands for a service discovery backend.
+	Registry interface {
+		Register(context.Context, Service) error
+		Deregister(context.Context, Service) error
+	}
+
+	// Service describes an instance of an app to discovery.
+	Service struct {
+		// Name is the name of the service the instance belongs to.
+		Name string
+		// ID identifies the instance.
+		ID string
+		// Address is the address the instance serves at.
+		Address string
+		// Port is the port the instance serves at.
+		Port int
+		// Meta is arbitrary metadata attached to the instance.
+		Meta map[string]string
+	}
+)
+
+// WithRegistration makes an app register the service with the registry once all the runners are
+// ready, and deregister it once the app starts draining, concurrently with the drainers (see the
+// chariot.Drainer interface), or the context provided to the runners is cancelled. Errors of the
+// registry are reported to the handler, if it isn't nil; an error of the registration fails
+// neither the run nor the app.
+func WithRegistration(registry Registry, service Service, handler func(error)) chariot.RunOption {
+	if handler == nil {
+		handler = func(error) {}
+	}
+	var registration sync.WaitGroup
+
+	return chariot.RunOption(chariot.WithOptions(
+		chariot.WithBeforeRun(func(ctx context.Context, app chariot.App) error {
+			registration.Add(1)
+			go func() {
+				defer registration.Done()
+
+				select {
+				case <-app.Ready():
+				case <-ctx.Done():
+					return
+				}
+				if err := registry.Register(ctx, service); err != nil {
+					handler(err)
+
+					return
+				}
+
+				select {
+				case <-app.Draining():
+				case <-ctx.Done():
+				}
+				if err := registry.Deregister(context.WithoutCancel(ctx), service); err != nil {
+					handler(err)
+				}
+			}()
+
+			return nil
+		}),
+		chariot.WithAfterRun(func(context.Context, chariot.App, error) {
+			registration.Wait()
+		}),
+	))
+}
@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/discovery"
+)
+
+type registry struct {
+	mu           sync.Mutex
+	events       []string
+	fail         bool
+	registered   chan struct{}
+	deregistered chan struct{}
+}
+
+type server struct {
+	registered <-chan struct{}
+}
+
+type consumer struct {
+	deregistered <-chan struct{}
+}
+
+func (r *registry) Register(_ context.Context, service discovery.Service) error {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fail {
+		return errors.New("failure")
+	}
+	r.events = append(r.events, "register "+service.ID)
+	close(r.registered)
+
+	return nil
+}
+
+func (r *registry) Deregister(ctx context.Context, service discovery.Service) error {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	r.events = append(r.events, "deregister "+service.ID)
+	if r.deregistered != nil {
+		close(r.deregistered)
+	}
+
+	return nil
+}
+
+func (s server) Run(ctx context.Context) error {
+
+	<-s.registered
+
+	return nil
+}
+
+func (c consumer) Run(ctx context.Context) error {
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func (c consumer) Drain(ctx context.Context) error {
+
+	select {
+	case <-c.deregistered:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestWithRegistration(t *testing.T) {
+
+	t.Run("lifecycle", func(t *testing.T) {
+
+		r := registry{registered: make(chan struct{})}
+
+		app, err := chariot.New(chariot.WithComponents(server{r.registered}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(discovery.WithRegistration(&r, discovery.Service{ID: "1"}, func(err error) {
+
+			t.Error(err)
+		})); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(r.events) != 2 || r.events[0] != "register 1" || r.events[1] != "deregister 1" {
+			t.Fatal(r.events)
+		}
+	})
+
+	t.Run("draining", func(t *testing.T) {
+
+		r := registry{registered: make(chan struct{}), deregistered: make(chan struct{})}
+
+		app, err := chariot.New(chariot.WithComponents(consumer{r.deregistered}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run(discovery.WithRegistration(&r, discovery.Service{ID: "1"}, func(err error) {
+
+				t.Error(err)
+			}))
+		}()
+		<-r.registered
+
+		app.Shutdown(chariot.WithShutdownErrorHandler(func(_ context.Context, err error) {
+
+			t.Error(err)
+		}))
+		if err := <-ran; err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		r := registry{fail: true}
+
+		var reported error
+		failed := make(chan struct{})
+
+		app, err := chariot.New(chariot.WithComponents(server{failed}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(discovery.WithRegistration(&r, discovery.Service{}, func(err error) {
+
+			reported = err
+			close(failed)
+		})); err != nil {
+			t.Fatal(err)
+		}
+
+		if reported == nil {
+			t.FailNow()
+		}
+	})
+}
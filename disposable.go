@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"sync"
+)
+
+// Disposable holds a memory-heavy value, e.g. a large in-memory index, that may be released
+// mid-run to reclaim its memory and is rebuilt on the next access. A component holding it is
+// meant to be of a type of its own, e.g. a struct embedding a *Disposable, so that several of them
+// are told apart. A Disposable is a Shutdowner: it's disposed of once the app is shut down.
+type Disposable struct {
+	build func(context.Context) (interface{}, error)
+
+	mu    sync.Mutex
+	value interface{}
+	built bool
+}
+
+// NewDisposable makes a Disposable with the function building the value. The value isn't built
+// till it's first accessed.
+func NewDisposable(build func(context.Context) (interface{}, error)) *Disposable {
+	return &Disposable{build: build}
+}
+
+// Get returns the value, building it first if it has been disposed of or hasn't been built yet.
+// An error of the build is returned as is, and the build is retried on the next access.
+func (d *Disposable) Get(ctx context.Context) (interface{}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.built {
+		return d.value, nil
+	}
+
+	value, err := d.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.value, d.built = value, true
+
+	return value, nil
+}
+
+// Dispose releases the value so that its memory can be reclaimed. A Shutdowner-conformant value is
+// shut down with the context given.
+func (d *Disposable) Dispose(ctx context.Context) {
+	d.mu.Lock()
+	value, built := d.value, d.built
+	d.value, d.built = nil, false
+	d.mu.Unlock()
+
+	if shutdowner, ok := value.(Shutdowner); built && ok {
+		shutdowner.Shutdown(ctx)
+	}
+}
+
+// Shutdown disposes of the value.
+func (d *Disposable) Shutdown(ctx context.Context) {
+	d.Dispose(ctx)
+}
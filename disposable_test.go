@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+type index struct {
+	*chariot.Disposable
+}
+
+func TestDisposable(t *testing.T) {
+
+	t.Run("rebuild", func(t *testing.T) {
+
+		var builds, shutdowns int
+
+		app, err := chariot.New(chariot.With(func() index {
+
+			return index{chariot.NewDisposable(func(context.Context) (interface{}, error) {
+
+				builds++
+
+				var a A
+				a.mocks.Shutdown = func(context.Context) {
+
+					shutdowns++
+				}
+
+				return a, nil
+			})}
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var i index
+		if !app.Retrieve(&i) {
+			t.FailNow()
+		}
+
+		for n := 0; n < 2; n++ {
+			if _, err := i.Get(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if builds != 1 {
+			t.Fatal(builds)
+		}
+
+		i.Dispose(context.Background())
+		if _, err := i.Get(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		app.Shutdown()
+		if builds != 2 || shutdowns != 2 {
+			t.Fatal(builds, shutdowns)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		expected := errors.New("failure")
+
+		d := chariot.NewDisposable(func(context.Context) (interface{}, error) {
+
+			return nil, expected
+		})
+		if _, err := d.Get(context.Background()); err != expected {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Drainer stands for a Runner-conformant component, e.g. a queue consumer, that is to be drained
+// when an app is shut down or receives a signal (see the WithSignals option) while running: the
+// Drain method is invoked before the runners' context is cancelled, so the component stops the
+// intake, finishes the work in flight and commits its progress, e.g. offsets, deterministically.
+// Drainers are invoked concurrently, bounded by the run exit timeout (see the WithRunExitTimeout
+// option), and their errors are reported the way other errors of the shutdown are (see the
+// WithShutdownErrorHandler option). The channel the Draining method of the app returns is closed
+// as drainers are invoked, e.g. to deregister the app from service discovery meanwhile.
+type Drainer interface {
+	Drain(context.Context) error
+}
+
+// Drain drains the runners of the embedded app.
+func (e embeddedApp) Drain(ctx context.Context) error {
+	return e.App.drain(ctx)
+}
+
+// Draining returns a channel that's closed once the app starts draining, i.e. it's either shut down
+// or receives a signal while running, before the context provided to the runners is cancelled (see
+// the Drainer interface).
+func (a App) Draining() <-chan struct{} {
+	return a.draining
+}
+
+// drainBounded drains the app bounded by the run exit timeout, and reports the errors of the
+// drainers to the shutdown error handler.
+func (a App) drainBounded(ctx context.Context, options options) {
+	drainCtx, cancel := context.WithTimeout(ctx, options.runExitTimeout)
+	defer cancel()
+
+	if err := a.drain(drainCtx); err != nil {
+		options.handler(ctx, err)
+	}
+}
+
+// drain drains the running runners conformant to the Drainer interface concurrently, each at most
+// once, and reports the errors of the drainers.
+func (a App) drain(ctx context.Context) error {
+	a.drainOnce.Do(func() {
+		close(a.draining)
+	})
+
+	a.mu.RLock()
+	runners := a.runners
+	a.mu.RUnlock()
+
+	var (
+		mu        sync.Mutex
+		drainErrs []error
+		drained   sync.WaitGroup
+	)
+	for _, runner := range runners {
+		drainer, ok := runner.Runner.(Drainer)
+		if !ok || !runner.startDraining() {
+			continue
+		}
+
+		drained.Add(1)
+		go func() {
+			defer drained.Done()
+
+			if err := drainer.Drain(ctx); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+
+				drainErrs = append(drainErrs, fmt.Errorf("drainer '%s': %w", nameOf(drainer), err))
+			}
+		}()
+	}
+	drained.Wait()
+
+	return errors.Join(drainErrs...)
+}
+
+// startDraining reports whether the runner is to be drained, i.e. it's running and hasn't been
+// drained before.
+func (r *managedRunner) startDraining() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.drained || r.state != RunnerRunning {
+		return false
+	}
+	r.drained = true
+
+	return true
+}
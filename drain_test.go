@@ -0,0 +1,179 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+type consumer struct {
+	mu     sync.Mutex
+	events []string
+	err    error
+}
+
+func (c *consumer) Run(ctx context.Context) error {
+
+	<-ctx.Done()
+	c.record("cancelled")
+
+	return nil
+}
+
+func (c *consumer) Drain(context.Context) error {
+
+	c.record("drained")
+
+	return c.err
+}
+
+func (c *consumer) record(event string) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.events = append(c.events, event)
+}
+
+func TestDrainer(t *testing.T) {
+
+	t.Run("before-cancellation", func(t *testing.T) {
+
+		c := new(consumer)
+
+		app, err := chariot.New(chariot.WithComponents(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run()
+		}()
+		<-app.Ready()
+
+		app.Shutdown()
+		if err := <-ran; err != nil {
+			t.Fatal(err)
+		}
+
+		if len(c.events) != 2 || c.events[0] != "drained" || c.events[1] != "cancelled" {
+			t.Fatal(c.events)
+		}
+	})
+
+	t.Run("signal", func(t *testing.T) {
+
+		switch runtime.GOOS {
+		case "windows", "js", "wasip1":
+			t.Skip("interrupts can't be sent on", runtime.GOOS)
+		}
+
+		c := new(consumer)
+
+		app, err := chariot.New(chariot.WithComponents(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run()
+		}()
+		<-app.Ready()
+
+		process, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := process.Signal(os.Interrupt); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-ran; err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case <-app.Draining():
+		default:
+			t.FailNow()
+		}
+		if len(c.events) != 2 || c.events[0] != "drained" || c.events[1] != "cancelled" {
+			t.Fatal(c.events)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+
+		testErr := errors.New("test")
+		c := &consumer{err: testErr}
+
+		app, err := chariot.New(chariot.WithComponents(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run()
+		}()
+		<-app.Ready()
+
+		var reported error
+		app.Shutdown(chariot.WithShutdownErrorHandler(func(_ context.Context, err error) {
+
+			reported = err
+		}))
+		<-ran
+
+		if !errors.Is(reported, testErr) {
+			t.Fatal(reported)
+		}
+	})
+
+	t.Run("not-running", func(t *testing.T) {
+
+		c := new(consumer)
+
+		app, err := chariot.New(chariot.WithComponents(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+
+		if len(c.events) != 0 {
+			t.Fatal(c.events)
+		}
+	})
+}
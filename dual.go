@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import "context"
+
+// RunnerShutdownPolicy controls the way the Shutdown method of a component conformant to both the
+// Runner and the Shutdowner interfaces, e.g. a server, is invoked when an app is shut down (see the
+// WithRunnerShutdown option).
+type RunnerShutdownPolicy int
+
+const (
+	// ShutdownAfterExit means the Shutdown method is invoked along with the rest of the
+	// shutdowners, after the runners have exited, whether or not the Run method has returned on
+	// its own before. It's the default.
+	ShutdownAfterExit RunnerShutdownPolicy = iota
+	// ShutdownUnlessExited means the Shutdown method is invoked as with ShutdownAfterExit unless
+	// the Run method had returned before the shutdown began, e.g. failing, which is taken as the
+	// component having released its resources, so that it isn't closed twice.
+	ShutdownUnlessExited
+	// ShutdownToStop means the Shutdown method of a component still running is invoked to stop it
+	// right after the runners' context is cancelled rather than after the runners have exited,
+	// e.g. for a server whose Run method doesn't watch the context, like the ListenAndServe method
+	// of http.Server. The Shutdown method of a component whose Run method had returned before the
+	// shutdown began isn't invoked, as with ShutdownUnlessExited.
+	ShutdownToStop
+)
+
+// WithRunnerShutdown provides the policy of invoking the Shutdown method of components conformant
+// to both the Runner and the Shutdowner interfaces. The Shutdown method of such a component is
+// invoked at most once regardless of the policy.
+func WithRunnerShutdown(policy RunnerShutdownPolicy) ShutdownOption {
+	return func(options *options) {
+		options.runnerShutdown = policy
+	}
+}
+
+// runnerShutdowner is a Shutdowner-conformant component along with the runner it is as well.
+type runnerShutdowner struct {
+	Shutdowner
+	runner *managedRunner
+}
+
+// Name returns the name of the component.
+func (s runnerShutdowner) Name() string {
+	return nameOf(s.Shutdowner)
+}
+
+// settledShutdowners reports which of the shutdowners aren't to be invoked according to the
+// policy as their runners have exited before the shutdown began.
+func settledShutdowners(shutdowners []Shutdowner, policy RunnerShutdownPolicy) []bool {
+	settled := make([]bool, len(shutdowners))
+	if policy == ShutdownAfterExit {
+		return settled
+	}
+
+	for i, shutdowner := range shutdowners {
+		if paired, ok := shutdowner.(runnerShutdowner); ok && paired.runner.exited() {
+			settled[i] = true
+		}
+	}
+
+	return settled
+}
+
+// stopRunners invokes the shutdowners of the runners that haven't exited in the reverse order, if
+// the policy is ShutdownToStop, and marks them settled.
+func (a App) stopRunners(
+	ctx context.Context,
+	shutdowners []Shutdowner,
+	settled []bool,
+	options options,
+) {
+	if options.runnerShutdown != ShutdownToStop {
+		return
+	}
+
+	for i := len(shutdowners) - 1; i >= 0; i-- {
+		if _, ok := shutdowners[i].(runnerShutdowner); !ok || settled[i] {
+			continue
+		}
+
+		a.invokeShutdowner(ctx, shutdowners[i], options)
+		settled[i] = true
+	}
+}
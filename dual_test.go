@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+// blockingServer neglects the context passed to the Run method, the way http.Server's
+// ListenAndServe does, and is only stopped by the Shutdown method.
+type blockingServer struct {
+	stopped   chan struct{}
+	shutdowns *int
+}
+
+func (s blockingServer) Run(context.Context) error {
+
+	<-s.stopped
+
+	return nil
+}
+
+func (s blockingServer) Shutdown(context.Context) {
+
+	*s.shutdowns++
+	close(s.stopped)
+}
+
+func TestWithRunnerShutdown(t *testing.T) {
+
+	t.Run("unless-exited", func(t *testing.T) {
+
+		var shutdowns int
+
+		app, err := chariot.New(chariot.With(func() A {
+
+			var a A
+			a.mocks.Run = func(context.Context) error {
+
+				return errors.New("failure")
+			}
+			a.mocks.Shutdown = func(context.Context) {
+
+				shutdowns++
+			}
+
+			return a
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.Run(); err == nil {
+			t.FailNow()
+		}
+		app.Shutdown(chariot.WithRunnerShutdown(chariot.ShutdownUnlessExited))
+
+		if shutdowns != 0 {
+			t.Fatal(shutdowns)
+		}
+	})
+
+	t.Run("to-stop", func(t *testing.T) {
+
+		var shutdowns int
+
+		app, err := chariot.New(chariot.WithComponents(blockingServer{
+			stopped:   make(chan struct{}),
+			shutdowns: &shutdowns,
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runErr := make(chan error, 1)
+		go func() {
+
+			runErr <- app.Run()
+		}()
+		<-app.Ready()
+
+		started := time.Now()
+		app.Shutdown(
+			chariot.WithRunnerShutdown(chariot.ShutdownToStop),
+			chariot.WithRunExitTimeout(time.Minute),
+		)
+
+		switch {
+		case time.Since(started) > time.Second:
+			t.Fatal(time.Since(started))
+		case shutdowns != 1:
+			t.Fatal(shutdowns)
+		}
+		if err := <-runErr; err != nil {
+			t.Fatal(err)
+		}
+	})
+}
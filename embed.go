@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// embeddedApp chains the lifecycle of an embedded app to the one of the app embedding it.
+type embeddedApp struct {
+	App
+}
+
+// WithEmbedded embeds a separately initialized app, e.g. a reusable platform app embedded by
+// product apps. Components of the listed types are re-exported: they're provided by the embedded
+// app as if they were components of the app embedding it. The lifecycle of the embedded app is
+// chained to the one of the app embedding it: the former is run as a runner of the latter, and shut
+// down after all its shutdowners. Re-exported runners and shutdowners are run and shut down by the
+// embedded app alone. An invalid app or a type the app lacks a component of causes an error.
+func WithEmbedded(app App, types ...reflect.Type) Option {
+	site := callSite()
+
+	return func(options *options) {
+		if !app.Valid() {
+			options.errs = append(options.errs, fmt.Errorf("app embedded at %s is invalid", site))
+
+			return
+		}
+
+		for _, componentType := range types {
+			if _, _, found := app.lookup(componentType); !found {
+				options.errs = append(options.errs, fmt.Errorf(
+					"app embedded at %s is missing component '%s'",
+					site,
+					componentType,
+				))
+
+				continue
+			}
+
+			options.initializers = append(options.initializers, reExporter(app, componentType))
+		}
+		options.embedded = append(options.embedded, app)
+	}
+}
+
+// Run runs the embedded app.
+func (e embeddedApp) Run(ctx context.Context) error {
+	return e.App.Run(WithRunContext(ctx))
+}
+
+// Shutdown shuts down the embedded app.
+func (e embeddedApp) Shutdown(ctx context.Context) {
+	e.App.Shutdown(WithShutdownContext(ctx))
+}
+
+// Name names the embedded app.
+func (embeddedApp) Name() string {
+	return "embedded app"
+}
+
+// reExporter makes a constructor providing a component of the embedded app. The constructor is
+// annotated as one re-exporting, so the app embedding doesn't manage the lifecycle of the
+// component.
+func reExporter(app App, componentType reflect.Type) interface{} {
+	constructor := reflect.MakeFunc(
+		reflect.FuncOf(nil, []reflect.Type{componentType, errorType}, false),
+		func([]reflect.Value) []reflect.Value {
+			value := reflect.New(componentType)
+			if err := app.RetrieveCtx(app.ctx, value.Interface()); err != nil {
+				return []reflect.Value{
+					value.Elem(),
+					reflect.ValueOf(fmt.Errorf("embedded app: %w", err)),
+				}
+			}
+
+			return []reflect.Value{
+				value.Elem(),
+				reflect.Zero(errorType),
+			}
+		},
+	).Interface()
+
+	return annotate(constructor, func(annotation *annotation) {
+		annotation.reExported = true
+	})
+}
+
+// embed chains the lifecycles of the embedded apps to the one of the app. It's done once the app
+// is initialized, so a failure to initialize leaves the embedded apps intact, and the embedded apps
+// are placed at the head of the shutdowners to be shut down last.
+func (a App) embed(apps []App) {
+	if len(apps) == 0 {
+		return
+	}
+
+	runners := make([]*managedRunner, 0, len(apps)+len(a.runners))
+	shutdowners := make([]Shutdowner, 0, len(apps)+len(a.shutdowners))
+	for _, app := range apps {
+		embedded := embeddedApp{app}
+		runner := &managedRunner{Runner: embedded}
+		runners = append(runners, runner)
+		shutdowners = append(shutdowners, runnerShutdowner{Shutdowner: embedded, runner: runner})
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.runners = append(runners, a.runners...)
+	a.shutdowners = append(shutdowners, a.shutdowners...)
+}
@@ -0,0 +1,153 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithEmbedded(t *testing.T) {
+
+	t.Run("lifecycle", func(t *testing.T) {
+
+		var (
+			ran   bool
+			order []string
+		)
+
+		var a A
+		a.mocks.Run = func(context.Context) error {
+
+			ran = true
+
+			return nil
+		}
+		a.mocks.Shutdown = func(context.Context) {
+
+			order = append(order, "embedded")
+		}
+
+		c := new(C)
+
+		platform, err := chariot.New(chariot.WithComponents(a, c))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app, err := chariot.New(
+			chariot.WithEmbedded(platform, reflect.TypeOf(c)),
+			chariot.With(func(embedded *C) B {
+
+				if embedded != c {
+					t.FailNow()
+				}
+
+				var b B
+				b.mocks.Shutdown = func(context.Context) {
+
+					order = append(order, "embedding")
+				}
+
+				return b
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+
+		switch {
+		case !ran:
+			t.FailNow()
+		case len(order) != 2 || order[0] != "embedding" || order[1] != "embedded":
+			t.Fatal(order)
+		case platform.Retrieve(&c):
+			t.FailNow()
+		}
+	})
+
+	t.Run("re-exported-lifecycle", func(t *testing.T) {
+
+		var runs, shutdowns int32
+
+		var a A
+		a.mocks.Run = func(context.Context) error {
+
+			atomic.AddInt32(&runs, 1)
+
+			return nil
+		}
+		a.mocks.Shutdown = func(context.Context) {
+
+			atomic.AddInt32(&shutdowns, 1)
+		}
+
+		platform, err := chariot.New(chariot.WithComponents(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app, err := chariot.New(chariot.WithEmbedded(platform, reflect.TypeOf(a)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+
+		if runs != 1 || shutdowns != 1 {
+			t.Fatal(runs, shutdowns)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+
+		platform, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer platform.Shutdown()
+
+		if _, err := chariot.New(chariot.WithEmbedded(platform, reflect.TypeOf(new(C)))); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		if _, err := chariot.New(chariot.WithEmbedded(chariot.App{})); err == nil {
+			t.FailNow()
+		}
+	})
+}
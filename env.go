@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// WithEnvOptions configures an app via environment variables, so that operators can tweak its
+// lifecycle without a rebuild. The variables recognized are:
+//
+//	CHARIOT_DEBUG               a boolean enabling the chariot.verbose debug toggle, i.e. logging
+//	                            resolutions of components, and the WithIntrospection option
+//	CHARIOT_DETERMINISTIC       a boolean enabling the chariot.deterministic debug toggle, i.e.
+//	                            invoking inits one by one regardless of the WithParallelInits
+//	                            option
+//	CHARIOT_REJECT_NIL          a boolean enabling the WithRejectNil option
+//	CHARIOT_RUN_EXIT_TIMEOUT    a duration making the default of the WithRunExitTimeout option
+//	CHARIOT_SHUTDOWNER_TIMEOUT  a duration making the default of the WithShutdownerTimeout option
+//
+// Booleans and durations are parsed by strconv.ParseBool and time.ParseDuration respectively. An
+// unset or empty variable is ignored, while a malformed one causes an error. Options passed to the
+// Shutdown method explicitly take precedence over the defaults. The debug toggles are enabled on
+// the ones provided via the WithDebugToggles option, if any.
+func WithEnvOptions() Option {
+	return func(options *options) {
+		for _, flag := range []struct {
+			name    string
+			enabled *bool
+		}{
+			{"CHARIOT_DEBUG", &options.introspection},
+			{"CHARIOT_DEBUG", &options.verbose},
+			{"CHARIOT_DETERMINISTIC", &options.deterministic},
+			{"CHARIOT_REJECT_NIL", &options.rejectNil},
+		} {
+			name, enabled := flag.name, flag.enabled
+			raw := os.Getenv(name)
+			if raw == "" {
+				continue
+			}
+
+			value, err := strconv.ParseBool(raw)
+			if err != nil {
+				options.errs = append(options.errs, fmt.Errorf("environment variable %s: %w", name, err))
+
+				continue
+			}
+			*enabled = *enabled || value
+		}
+
+		for _, timeout := range []struct {
+			name   string
+			option func(time.Duration) ShutdownOption
+		}{
+			{"CHARIOT_RUN_EXIT_TIMEOUT", WithRunExitTimeout},
+			{"CHARIOT_SHUTDOWNER_TIMEOUT", WithShutdownerTimeout},
+		} {
+			name, option := timeout.name, timeout.option
+			raw := os.Getenv(name)
+			if raw == "" {
+				continue
+			}
+
+			value, err := time.ParseDuration(raw)
+			if err != nil {
+				options.errs = append(options.errs, fmt.Errorf("environment variable %s: %w", name, err))
+
+				continue
+			}
+			options.shutdownDefaults = append(options.shutdownDefaults, option(value))
+		}
+	}
+}
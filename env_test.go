@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithEnvOptions(t *testing.T) {
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Setenv("CHARIOT_DEBUG", "true")
+		t.Setenv("CHARIOT_SHUTDOWNER_TIMEOUT", "10ms")
+
+		release := make(chan struct{})
+		defer close(release)
+
+		var a A
+		a.mocks.Shutdown = func(context.Context) {
+
+			<-release
+		}
+
+		app, err := chariot.New(chariot.WithComponents(a), chariot.WithEnvOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := app.Dependencies(new(A)); !ok {
+			t.FailNow()
+		}
+
+		shutdown := make(chan struct{})
+		go func() {
+
+			app.Shutdown(chariot.WithShutdownErrorHandler(func(context.Context, error) {}))
+			close(shutdown)
+		}()
+
+		select {
+		case <-shutdown:
+		case <-time.After(time.Second):
+			t.FailNow()
+		}
+	})
+
+	t.Run("toggles", func(t *testing.T) {
+
+		t.Setenv("CHARIOT_DEBUG", "true")
+		t.Setenv("CHARIOT_DETERMINISTIC", "1")
+
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer log.SetOutput(os.Stderr)
+
+		var (
+			mu    sync.Mutex
+			order []string
+		)
+		record := func(name string) {
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, name)
+		}
+
+		app, err := chariot.New(
+			chariot.WithEnvOptions(),
+			chariot.WithParallelInits(4),
+			chariot.With(
+				func() {
+
+					time.Sleep(5 * time.Millisecond)
+					record("first")
+				},
+				func() {
+
+					record("second")
+				},
+			),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var toggles *chariot.DebugToggles
+		switch {
+		case !app.Retrieve(&toggles):
+			t.FailNow()
+		case !toggles.Verbose() || !toggles.DeterministicOrder():
+			t.Fatal(toggles.Verbose(), toggles.DeterministicOrder())
+		case !reflect.DeepEqual(order, []string{"first", "second"}):
+			t.Fatal(order)
+		case !strings.Contains(logs.String(), "invoked initializer"):
+			t.Fatal(logs.String())
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+
+		t.Setenv("CHARIOT_RUN_EXIT_TIMEOUT", "soon")
+
+		if _, err := chariot.New(chariot.WithEnvOptions()); err == nil {
+			t.FailNow()
+		}
+	})
+}
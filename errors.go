@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	// ErrAppClosed is returned when an app is used after it has been shut down.
+	ErrAppClosed = errors.New("app closed")
+
+	// ErrAppRunning is returned when an app is run while it's already running.
+	ErrAppRunning = errors.New("app is already running")
+
+	// ErrAppNotRunning is returned when a sub-runner is spawned while an app isn't running (see
+	// the Spawner type).
+	ErrAppNotRunning = errors.New("app isn't running")
+
+	// ErrAppFinished is returned when an app is run after it has finished running. Runners aren't
+	// generally restartable, so an app runs at most once.
+	ErrAppFinished = errors.New("app has already finished running")
+
+	// ErrRunnerFailed is returned when a runner trips the circuit breaker of a restart policy.
+	ErrRunnerFailed = errors.New("runner failed repeatedly")
+
+	// ErrShutdownTimeout is reported when a shutdowner exceeds its timeout.
+	ErrShutdownTimeout = errors.New("shutdown timeout exceeded")
+
+	// ErrMissingDependency is returned when an initializer depends on a component no initializer
+	// provides.
+	ErrMissingDependency = errors.New("missing dependency")
+
+	// ErrDuplicateComponent is returned when more than one initializer provides a component of the
+	// same type.
+	ErrDuplicateComponent = errors.New("duplicating component")
+
+	// ErrCycle is returned when components depend on each other in a cycle, either by their
+	// dependencies or the order they're to start in (see the StartAfter function).
+	ErrCycle = errors.New("cycle detected")
+
+	// ErrMissingComponent is returned when an app is asked for a component it doesn't have.
+	ErrMissingComponent = errors.New("missing component")
+
+	// ErrNilComponent is returned when a nil component is rejected (see the WithRejectNil option).
+	ErrNilComponent = errors.New("nil component")
+
+	// ErrNotReady is returned when runners don't get ready within the window of a smoke run (see
+	// the WithSmokeRun option).
+	ErrNotReady = errors.New("runners not ready")
+
+	// ErrConfig is meant to be wrapped by errors caused by an invalid configuration. The ExitCode
+	// function maps it to 78 (EX_CONFIG).
+	ErrConfig = errors.New("invalid configuration")
+
+	// ErrUnavailable is meant to be wrapped by errors caused by an unavailable dependency, e.g. a
+	// database. The ExitCode function maps it to 69 (EX_UNAVAILABLE).
+	ErrUnavailable = errors.New("dependency unavailable")
+)
+
+// BuildError is returned when an app fails to initialize due to an initializer, and describes how
+// far the initialization went. Use errors.As to retrieve it.
+type BuildError struct {
+	// Err is the error the initialization failed with.
+	Err error
+	// Constructed are the types of the components constructed before the failure, in the order
+	// they were constructed in.
+	Constructed []reflect.Type
+	// Pending are the types of the components not constructed due to the failure, lazy ones
+	// excluded.
+	Pending []reflect.Type
+}
+
+// Error returns the message of the error the initialization failed with.
+func (e *BuildError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error the initialization failed with.
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
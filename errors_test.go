@@ -0,0 +1,113 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestSentinelErrors(t *testing.T) {
+
+	newC := func() *C {
+
+		return new(C)
+	}
+
+	for name, test := range map[string]struct {
+		options  []chariot.Option
+		expected error
+	}{
+		"missing dependency": {
+			options: []chariot.Option{chariot.With(func(*C) *D {
+
+				return new(D)
+			})},
+			expected: chariot.ErrMissingDependency,
+		},
+		"duplicate component": {
+			options:  []chariot.Option{chariot.With(newC, newC)},
+			expected: chariot.ErrDuplicateComponent,
+		},
+		"cycle": {
+			options: []chariot.Option{chariot.With(
+				func(*D) *C {
+
+					return new(C)
+				},
+				func(*C) *D {
+
+					return new(D)
+				},
+			)},
+			expected: chariot.ErrCycle,
+		},
+		"start order cycle": {
+			options: []chariot.Option{chariot.With(
+				chariot.StartAfter(func() A {
+
+					return A{}
+				}, reflect.TypeOf(B{})),
+				chariot.StartAfter(func() B {
+
+					return B{}
+				}, reflect.TypeOf(A{})),
+			)},
+			expected: chariot.ErrCycle,
+		},
+		"nil component": {
+			options: []chariot.Option{chariot.With(func() *C {
+
+				return nil
+			}), chariot.WithRejectNil()},
+			expected: chariot.ErrNilComponent,
+		},
+	} {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+
+			if _, err := chariot.New(test.options...); !errors.Is(err, test.expected) {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	t.Run("missing-component", func(t *testing.T) {
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var c *C
+		if err := app.RetrieveCtx(context.Background(), &c); !errors.Is(err, chariot.ErrMissingComponent) {
+			t.Fatal(err)
+		}
+	})
+}
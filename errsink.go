@@ -0,0 +1,157 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"sync"
+)
+
+// OverflowPolicy controls what happens to errors reported in the course of a run once the error
+// buffer is full (see the WithErrorBuffer option).
+type OverflowPolicy struct {
+	kind     overflowKind
+	callback func(error)
+}
+
+type overflowKind int
+
+const (
+	overflowBlock overflowKind = iota
+	overflowDropOldest
+	overflowCallback
+)
+
+var (
+	// OverflowBlock makes a runner or a sub-runner reporting an error wait until the context of
+	// the run is cancelled, after which the error is dropped. It holds back sub-runners being
+	// spawned (see the Spawner type). Runners are restarted rather than cancelled under the
+	// WithRestarts option, so the errors of the quarantined ones are dropped straight away.
+	OverflowBlock = OverflowPolicy{kind: overflowBlock}
+
+	// OverflowDropOldest makes the oldest error but the first one dropped in favour of the new one.
+	OverflowDropOldest = OverflowPolicy{kind: overflowDropOldest}
+)
+
+// OverflowCallback makes errors not fitting the buffer passed to the callback instead, e.g. to be
+// logged. The callback is invoked from the goroutine of the runner or sub-runner reporting.
+func OverflowCallback(callback func(error)) OverflowPolicy {
+	return OverflowPolicy{
+		kind:     overflowCallback,
+		callback: callback,
+	}
+}
+
+// WithErrorBuffer bounds the number of errors of runners, sub-runners and a smoke run the Run
+// method retains to return. The first error, which cancels the runners' context, is always
+// retained; the policy decides the fate of errors not fitting the buffer. Otherwise, all the
+// errors are retained. The number of errors dropped is reported by the RunReport method.
+func WithErrorBuffer(size int, policy OverflowPolicy) RunOption {
+	return func(options *options) {
+		options.errorBuffer = size
+		options.overflow = policy
+	}
+}
+
+// errorSink collects the errors reported in the course of a run.
+type errorSink struct {
+	ctx     context.Context
+	size    int
+	policy  OverflowPolicy
+	cancel  func()
+	onFirst func()
+
+	mu      sync.Mutex
+	errs    []error
+	dropped int
+}
+
+func newErrorSink(ctx context.Context, cancel func(), options options) *errorSink {
+	sink := errorSink{
+		ctx:    ctx,
+		size:   options.errorBuffer,
+		policy: options.overflow,
+		cancel: cancel,
+	}
+	if options.restartPolicy == nil {
+		sink.onFirst = cancel
+	}
+
+	return &sink
+}
+
+// reportFatal collects the error the way the report method does, and cancels the run regardless of
+// the restart policy, e.g. for sub-runners, which aren't restarted.
+func (s *errorSink) reportFatal(err error) {
+	s.cancel()
+	s.report(err)
+}
+
+// report collects the error according to the overflow policy.
+func (s *errorSink) report(err error) {
+	s.mu.Lock()
+	if len(s.errs) == 0 && s.onFirst != nil {
+		s.onFirst()
+	}
+	if s.size <= 0 || len(s.errs) < s.size {
+		s.errs = append(s.errs, err)
+		s.mu.Unlock()
+
+		return
+	}
+
+	switch s.policy.kind {
+	case overflowDropOldest:
+		if len(s.errs) > 1 {
+			copy(s.errs[1:], s.errs[2:])
+			s.errs[len(s.errs)-1] = err
+		}
+		s.dropped++
+		s.mu.Unlock()
+	case overflowCallback:
+		s.mu.Unlock()
+		s.policy.callback(err)
+	default:
+		// Nothing may cancel the run if the first error doesn't, so the error is dropped.
+		if s.onFirst == nil {
+			s.dropped++
+			s.mu.Unlock()
+
+			return
+		}
+		s.mu.Unlock()
+		<-s.ctx.Done()
+
+		s.mu.Lock()
+		s.dropped++
+		s.mu.Unlock()
+	}
+}
+
+// collected reports the errors retained and the number of the ones dropped.
+func (s *errorSink) collected() ([]error, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.errs, s.dropped
+}
@@ -0,0 +1,160 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithErrorBuffer(t *testing.T) {
+
+	newApp := func(t *testing.T, failures int) chariot.App {
+
+		app, err := chariot.New(chariot.With(func(spawner *chariot.Spawner) pool {
+
+			var failed int32
+
+			return pool{
+				spawner: spawner,
+				workers: failures,
+				run: func(context.Context) error {
+
+					return fmt.Errorf("failure #%d", atomic.AddInt32(&failed, 1))
+				},
+			}
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return app
+	}
+
+	t.Run("drop-oldest", func(t *testing.T) {
+
+		app := newApp(t, 5)
+		defer app.Shutdown()
+
+		report := app.RunReport(chariot.WithErrorBuffer(2, chariot.OverflowDropOldest))
+		switch {
+		case report.DroppedErrors != 3:
+			t.Fatal(report.DroppedErrors)
+		case len(report.Err.(Unwrapper).Unwrap()) != 2:
+			t.Fatal(report.Err)
+		case report.Trigger == nil:
+			t.FailNow()
+		}
+	})
+
+	t.Run("callback", func(t *testing.T) {
+
+		app := newApp(t, 5)
+		defer app.Shutdown()
+
+		var overflowed int32
+		report := app.RunReport(chariot.WithErrorBuffer(
+			1,
+			chariot.OverflowCallback(func(error) {
+
+				atomic.AddInt32(&overflowed, 1)
+			}),
+		))
+		switch {
+		case report.Err == nil:
+			t.FailNow()
+		case overflowed != 4:
+			t.Fatal(overflowed)
+		case report.DroppedErrors != 0:
+			t.Fatal(report.DroppedErrors)
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+
+		app := newApp(t, 3)
+		defer app.Shutdown()
+
+		report := app.RunReport(chariot.WithErrorBuffer(1, chariot.OverflowBlock))
+		switch {
+		case report.DroppedErrors != 2:
+			t.Fatal(report.DroppedErrors)
+		case len(report.Err.(Unwrapper).Unwrap()) != 1:
+			t.Fatal(report.Err)
+		}
+	})
+
+	t.Run("block-restarts", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(
+			func() A {
+
+				var a A
+				a.mocks.Run = func(context.Context) error {
+
+					return errors.New("a")
+				}
+
+				return a
+			},
+			func() B {
+
+				var b B
+				b.mocks.Run = func(context.Context) error {
+
+					return errors.New("b")
+				}
+
+				return b
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		reported := make(chan chariot.RunReport, 1)
+		go func() {
+
+			reported <- app.RunReport(
+				chariot.WithRestarts(1, time.Minute, time.Millisecond),
+				chariot.WithErrorBuffer(1, chariot.OverflowBlock),
+			)
+		}()
+
+		select {
+		case report := <-reported:
+			if report.DroppedErrors != 1 {
+				t.Fatal(report.DroppedErrors)
+			}
+		case <-time.After(time.Second):
+			t.FailNow()
+		}
+	})
+}
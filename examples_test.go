@@ -172,7 +172,7 @@ func ExampleWithInitContext() {
 	defer cancel()
 
 	app, err := chariot.New(
-		chariot.WithContext(ctx),
+		chariot.WithInitContext(ctx),
 		chariot.With(
 			NewConfig,
 			NewServer,
@@ -184,3 +184,41 @@ func ExampleWithInitContext() {
 	}
 	defer app.Shutdown()
 }
+
+func ExampleHealthProbeMain() {
+	// Given the binary is invoked with the -healthcheck flag, the call doesn't return.
+	chariot.HealthProbeMain(chariot.WithProbeTarget("http://localhost:8081/healthz"))
+
+	app, err := chariot.New(chariot.With(
+		NewConfig,
+		NewServer,
+		NewHTTPClient,
+	))
+	if err != nil {
+		log.Fatalf("Failed to create an app: %s\n", err)
+	}
+	defer app.Shutdown()
+
+	if err := app.Run(); err != nil {
+		log.Fatalf("Failed running the app: %s\n", err)
+	}
+}
+
+func ExampleNewPlan() {
+	plan, err := chariot.NewPlan(chariot.With(
+		NewConfig,
+		NewServer,
+		NewHTTPClient,
+	))
+	if err != nil {
+		log.Fatalf("Failed to plan an app: %s\n", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		app, err := plan.Build(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to build an app: %s\n", err)
+		}
+		app.Shutdown()
+	}
+}
@@ -0,0 +1,127 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"errors"
+)
+
+// ExitCoder stands for any conformant error that carries the exit status of a process it causes
+// to exit.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+type (
+	// exitCodeMapping maps errors of a class to an exit status.
+	exitCodeMapping struct {
+		target error
+		code   int
+	}
+
+	// exitCodeError is an error of a run the options map to an exit status.
+	exitCodeError struct {
+		error
+		code int
+	}
+)
+
+// WithExitCode maps errors of a run matching the target (see errors.Is) to an exit status reported
+// by the ExitCode function. The mappings are matched in the order they were provided in.
+func WithExitCode(target error, code int) RunOption {
+	return func(options *options) {
+		options.exitCodes = append(options.exitCodes, exitCodeMapping{target, code})
+	}
+}
+
+// ExitCode maps an error to the exit status of a process, so orchestrators can tell failure modes
+// apart. The status carried by an ExitCoder-conformant error in the chain, including the mappings
+// of the WithExitCode option, is taken, ErrConfig maps to 78 and ErrUnavailable maps to 69. A nil
+// error and an error every joined error of which is a cancelled context, i.e. a clean exit upon a
+// signal, map to 0, and the rest of the errors map to 1.
+func ExitCode(err error) int {
+	var exitCoder ExitCoder
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitCoder):
+		return exitCoder.ExitCode()
+	case errors.Is(err, ErrConfig):
+		return 78
+	case errors.Is(err, ErrUnavailable):
+		return 69
+	case cancelledOnly(err):
+		return 0
+	default:
+		return 1
+	}
+}
+
+// cancelledOnly reports whether the error is a cancelled context and, if it joins errors, whether
+// each of them is, e.g. the runners exited upon a signal rather than because one of them failed.
+func cancelledOnly(err error) bool {
+	for err != nil {
+		if err == context.Canceled {
+			return true
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			errs := joined.Unwrap()
+			for _, err := range errs {
+				if !cancelledOnly(err) {
+					return false
+				}
+			}
+
+			return len(errs) != 0
+		}
+		err = errors.Unwrap(err)
+	}
+
+	return false
+}
+
+// mapExitCode wraps the error with the exit status of the first mapping it matches, if any.
+func (o options) mapExitCode(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, mapping := range o.exitCodes {
+		if errors.Is(err, mapping.target) {
+			return exitCodeError{err, mapping.code}
+		}
+	}
+
+	return err
+}
+
+// ExitCode returns the exit status the error is mapped to.
+func (e exitCodeError) ExitCode() int {
+	return e.code
+}
+
+// Unwrap returns the mapped error.
+func (e exitCodeError) Unwrap() error {
+	return e.error
+}
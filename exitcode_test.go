@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestExitCode(t *testing.T) {
+
+	t.Run("classes", func(t *testing.T) {
+
+		for err, expected := range map[error]int{
+			nil:              0,
+			context.Canceled: 0,
+			fmt.Errorf("port: %w", chariot.ErrConfig):    78,
+			fmt.Errorf("db: %w", chariot.ErrUnavailable): 69,
+			errors.New("failure"):                        1,
+			errors.Join(
+				fmt.Errorf("runner 'failing': %w", errors.New("boom")),
+				fmt.Errorf("runner 'waiting': %w", context.Canceled),
+			): 1,
+			errors.Join(
+				fmt.Errorf("runner 'a': %w", context.Canceled),
+				fmt.Errorf("runner 'b': %w", context.Canceled),
+			): 0,
+		} {
+			if code := chariot.ExitCode(err); code != expected {
+				t.Fatal(err, code)
+			}
+		}
+	})
+
+	t.Run("mapping", func(t *testing.T) {
+
+		expected := errors.New("failure")
+
+		var a A
+		a.mocks.Run = func(context.Context) error {
+
+			return expected
+		}
+
+		app, err := chariot.New(chariot.WithComponents(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		err = app.Run(chariot.WithExitCode(expected, 42))
+		switch {
+		case !errors.Is(err, expected):
+			t.Fatal(err)
+		case chariot.ExitCode(err) != 42:
+			t.Fatal(chariot.ExitCode(err))
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		var a A
+		a.mocks.Run = func(context.Context) error {
+
+			return errors.New("boom")
+		}
+
+		var b B
+		b.mocks.Run = func(ctx context.Context) error {
+
+			<-ctx.Done()
+
+			return ctx.Err()
+		}
+
+		app, err := chariot.New(chariot.WithComponents(a, b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		err = app.Run()
+		switch {
+		case !errors.Is(err, context.Canceled):
+			t.Fatal(err)
+		case chariot.ExitCode(err) != 1:
+			t.Fatal(chariot.ExitCode(err))
+		}
+	})
+}
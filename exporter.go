@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+// MetricKind is the kind of a metric reported to exporters.
+type MetricKind int
+
+const (
+	// MetricGauge is a value as of the moment it's reported.
+	MetricGauge MetricKind = iota
+	// MetricCounter is an increment of a monotonically increasing value.
+	MetricCounter
+	// MetricTiming is an observed duration, in seconds.
+	MetricTiming
+)
+
+// Metric is a measurement of the framework's own.
+type Metric struct {
+	// Name is the name of the metric, e.g. "chariot_init_duration_seconds".
+	Name string
+	// Kind is the kind of the metric.
+	Kind MetricKind
+	// Labels are the dimensions of the measurement, e.g. the name of the initializer.
+	Labels map[string]string
+	// Value is the measured value.
+	Value float64
+}
+
+// Exporter receives the framework's own metrics, so that they're shipped to a backend of choice,
+// e.g. Prometheus, StatsD or OTLP, without chariot depending on a client library of any. Exporters
+// are invoked synchronously and concurrently, so they're to return promptly.
+type Exporter interface {
+	Export(Metric)
+}
+
+// ExporterFunc is a function conforming to the Exporter interface.
+type ExporterFunc func(Metric)
+
+// WithExporter makes an app report its metrics to the exporter. The metrics are:
+//
+//	chariot_init_duration_seconds     timing of an initializer, labelled by "initializer"
+//	chariot_runner_transitions_total  counter of transitions of a runner to a state, labelled by
+//	                                  "runner" and "state"
+//	chariot_shutdown_duration_seconds timing of a shutdown, labelled by "reason"
+//
+// The option may be provided multiple times to report to several exporters. Scopes of the app
+// (see the Scope method) don't inherit the exporters.
+func WithExporter(exporter Exporter) Option {
+	return func(options *options) {
+		options.exporters = append(options.exporters, exporter)
+	}
+}
+
+// Export delegates the export to the receiver.
+func (e ExporterFunc) Export(metric Metric) {
+	e(metric)
+}
+
+// export reports the metric to the exporters.
+func export(exporters []Exporter, metric Metric) {
+	for _, exporter := range exporters {
+		exporter.Export(metric)
+	}
+}
+
+// exportInit reports the duration of an initializer unless it's one generated for a component
+// provided as a value.
+func (a App) exportInit(initializer *node, seconds float64) {
+	if len(a.exporters) == 0 || initializer.fromValue {
+		return
+	}
+
+	export(a.exporters, Metric{
+		Name:   "chariot_init_duration_seconds",
+		Kind:   MetricTiming,
+		Labels: map[string]string{"initializer": funcName(initializer.initializer)},
+		Value:  seconds,
+	})
+}
@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithExporter(t *testing.T) {
+
+	var (
+		mu      sync.Mutex
+		metrics []chariot.Metric
+	)
+	exporter := chariot.ExporterFunc(func(metric chariot.Metric) {
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		metrics = append(metrics, metric)
+	})
+
+	app, err := chariot.New(
+		chariot.With(func() *C {
+
+			return new(C)
+		}),
+		chariot.WithComponents(namedRunner(func(context.Context) error {
+
+			return nil
+		})),
+		chariot.WithExporter(exporter),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Run(); err != nil {
+		t.Fatal(err)
+	}
+	app.Shutdown()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var inits, transitions, shutdowns int
+	for _, metric := range metrics {
+		switch metric.Name {
+		case "chariot_init_duration_seconds":
+			inits++
+			if metric.Kind != chariot.MetricTiming || metric.Labels["initializer"] == "" {
+				t.Fatal(metric)
+			}
+		case "chariot_runner_transitions_total":
+			transitions++
+			if metric.Kind != chariot.MetricCounter || metric.Labels["runner"] != "named" {
+				t.Fatal(metric)
+			}
+		case "chariot_shutdown_duration_seconds":
+			shutdowns++
+			if metric.Kind != chariot.MetricTiming || metric.Labels["reason"] != "explicit" {
+				t.Fatal(metric)
+			}
+		default:
+			t.Fatal(metric)
+		}
+	}
+	if inits != 1 || transitions != 2 || shutdowns != 1 {
+		t.Fatal(metrics)
+	}
+}
@@ -0,0 +1,152 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ComponentInfo describes the consumer of a component constructed by a factory (see the Factory
+// function), e.g. for a logger to be named after it. A factory gets it by depending on it.
+type ComponentInfo struct {
+	// Type is the type of the first component the consumer constructs; nil if the consumer is an
+	// init or the component is retrieved from the app.
+	Type reflect.Type
+	// Initializer is the name of the consumer; empty if the component is retrieved from the app.
+	Initializer string
+}
+
+var componentInfoType = reflect.TypeOf(ComponentInfo{})
+
+// Factory annotates a constructor as one invoked anew for each consumer of its component rather
+// than once: for each initializer depending on it and each retrieval from the app. The constructor
+// may depend on a ComponentInfo describing the consumer, and it must construct exactly one
+// component. Runner- and Shutdowner-conformant components constructed by a factory are managed as
+// usual, yet a runner constructed by retrieval is only run if the app's Run method is invoked
+// afterwards. The result is to be provided in place of the constructor.
+func Factory(constructor interface{}) interface{} {
+	return annotate(constructor, func(annotation *annotation) {
+		annotation.factory = true
+	})
+}
+
+// checkFactory ensures a factory constructs a single component and only a factory depends on a
+// ComponentInfo.
+func (Plan) checkFactory(node *node) error {
+	if node.factory && len(node.signature.components) != 1 {
+		return fmt.Errorf(
+			"factory '%s' constructs %d components, expected one",
+			node.name(),
+			len(node.signature.components),
+		)
+	}
+
+	if node.factory {
+		return nil
+	}
+	for _, dependencyType := range node.dependencies {
+		if dependencyType == componentInfoType {
+			return fmt.Errorf(
+				"initializer '%s' depends on '%s' without being annotated as a factory",
+				node.name(),
+				componentInfoType,
+			)
+		}
+	}
+
+	return nil
+}
+
+// valueFor reports the value of a component for a consumer, producing it if the component is of a
+// factory. A nil consumer stands for a retrieval from the app.
+func (a App) valueFor(
+	ctx context.Context,
+	component *component,
+	consumer *node,
+) (reflect.Value, error) {
+	component.markUsed()
+	a.mu.RLock()
+	value, producer := component.value, component.node
+	a.mu.RUnlock()
+	if value.IsValid() || producer == nil || !producer.factory {
+		return a.valueOf(ctx, component)
+	}
+
+	return a.produce(producer, consumer, func(dependencyType reflect.Type) (reflect.Value, error) {
+		owner, dependency, _ := a.lookup(dependencyType)
+
+		return owner.valueFor(ctx, dependency, producer)
+	})
+}
+
+// produce invokes a factory for a consumer resolving the rest of its dependencies with the
+// function.
+func (a App) produce(
+	factory *node,
+	consumer *node,
+	resolve func(reflect.Type) (reflect.Value, error),
+) (reflect.Value, error) {
+	ins := make([]reflect.Value, 0, len(factory.dependencies))
+	for _, dependencyType := range factory.dependencies {
+		if dependencyType == componentInfoType {
+			ins = append(ins, reflect.ValueOf(infoOf(consumer)))
+
+			continue
+		}
+
+		in, err := resolve(dependencyType)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ins = append(ins, in)
+	}
+
+	outs, err := a.call(factory, a.identify(factory, ins))
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.manage(factory, factory.signature.components[0], outs[0])
+
+	return outs[0], nil
+}
+
+func infoOf(consumer *node) ComponentInfo {
+	if consumer == nil {
+		return ComponentInfo{}
+	}
+
+	info := ComponentInfo{
+		Initializer: funcName(consumer.initializer),
+	}
+	if len(consumer.signature.components) != 0 {
+		info.Type = consumer.signature.components[0]
+	}
+
+	return info
+}
@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+type logger struct {
+	name string
+}
+
+func TestFactory(t *testing.T) {
+
+	t.Run("per-consumer", func(t *testing.T) {
+
+		var consumers []*logger
+
+		app, err := chariot.New(chariot.With(
+			chariot.Factory(func(info chariot.ComponentInfo) *logger {
+
+				name := "app"
+				if info.Type != nil {
+					name = info.Type.String()
+				}
+
+				return &logger{name: name}
+			}),
+			func(l *logger) *C {
+
+				consumers = append(consumers, l)
+
+				return new(C)
+			},
+			func(l *logger) *D {
+
+				consumers = append(consumers, l)
+
+				return new(D)
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var retrieved *logger
+		switch {
+		case len(consumers) != 2:
+			t.FailNow()
+		case consumers[0].name != reflect.TypeOf((*C)(nil)).String():
+			t.Fatal(consumers[0].name)
+		case consumers[1].name != reflect.TypeOf((*D)(nil)).String():
+			t.Fatal(consumers[1].name)
+		case !app.Retrieve(&retrieved):
+			t.FailNow()
+		case retrieved.name != "app":
+			t.Fatal(retrieved.name)
+		}
+	})
+
+	t.Run("lazy-consumer", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(
+			chariot.Factory(func(info chariot.ComponentInfo) *logger {
+
+				return &logger{name: info.Initializer}
+			}),
+			chariot.Lazy(func(l *logger) *C {
+
+				if l.name == "" {
+					t.Error("no consumer name")
+				}
+
+				return new(C)
+			}),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var c *C
+		if !app.Retrieve(&c) {
+			t.FailNow()
+		}
+	})
+
+	t.Run("not-a-factory", func(t *testing.T) {
+
+		_, err := chariot.New(chariot.With(func(chariot.ComponentInfo) *C {
+
+			return new(C)
+		}))
+		if err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("several-components", func(t *testing.T) {
+
+		_, err := chariot.New(chariot.With(chariot.Factory(func() (*C, *D) {
+
+			return new(C), new(D)
+		})))
+		if err == nil {
+			t.FailNow()
+		}
+	})
+}
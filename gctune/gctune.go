@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package gctune configures the garbage collector at the initialization of an app, so that the
+// runtime tuning is part of the dependency graph rather than scattered over main functions. The
+// Config struct is meant to be loaded by the config package along with the rest of the configs:
+//
+//	chariot.New(
+//		config.Module(&AppConfig{}, &gctune.Config{}),
+//		gctune.Module(),
+//	)
+//
+// The Tuner component reports the values in effect, which the console prints, e.g. with
+// "get *gctune.Tuner".
+package gctune
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"runtime/metrics"
+
+	"github.com/rwyyr/chariot"
+)
+
+const (
+	percentMetric = "/gc/gogc:percent"
+	limitMetric   = "/gc/gomemlimit:bytes"
+)
+
+type (
+	// Config is the configuration of the garbage collector. A zero field leaves the respective
+	// setting intact, e.g. as set by the GOGC or GOMEMLIMIT environment variables.
+	Config struct {
+		// Percent is the garbage collection target percentage (see debug.SetGCPercent).
+		Percent int `env:"GC_PERCENT" usage:"garbage collection target percentage, negative to disable"`
+		// MemoryLimit is the soft memory limit in bytes (see debug.SetMemoryLimit).
+		MemoryLimit int64 `env:"GC_MEMORY_LIMIT" usage:"soft memory limit in bytes"`
+	}
+
+	// Tuner is a Shutdowner-conformant component configuring the garbage collector once made, and
+	// restoring the previous settings once shut down.
+	Tuner struct {
+		percent    int
+		limit      int64
+		setPercent bool
+		setLimit   bool
+	}
+)
+
+// Module provides the Tuner component configuring the garbage collector according to the Config
+// component to an app.
+func Module() chariot.Module {
+	return chariot.With(func(config *Config) (*Tuner, error) {
+		return New(*config)
+	})
+}
+
+// New configures the garbage collector according to the config. An error is returned if the
+// memory limit is negative.
+func New(config Config) (*Tuner, error) {
+	if config.MemoryLimit < 0 {
+		return nil, fmt.Errorf("gctune: negative memory limit %d", config.MemoryLimit)
+	}
+
+	var tuner Tuner
+	if config.Percent != 0 {
+		tuner.percent, tuner.setPercent = debug.SetGCPercent(config.Percent), true
+	}
+	if config.MemoryLimit != 0 {
+		tuner.limit, tuner.setLimit = debug.SetMemoryLimit(config.MemoryLimit), true
+	}
+
+	return &tuner, nil
+}
+
+// Percent reports the garbage collection target percentage in effect, negative if the collection
+// is disabled.
+func (t *Tuner) Percent() int {
+	return int(readMetric(percentMetric))
+}
+
+// MemoryLimit reports the soft memory limit in effect, in bytes.
+func (t *Tuner) MemoryLimit() int64 {
+	return int64(readMetric(limitMetric))
+}
+
+// String describes the settings in effect.
+func (t *Tuner) String() string {
+	percent := fmt.Sprint(t.Percent())
+	if t.Percent() < 0 {
+		percent = "off"
+	}
+
+	return fmt.Sprintf("gogc=%s gomemlimit=%d", percent, t.MemoryLimit())
+}
+
+// Shutdown restores the previous settings.
+func (t *Tuner) Shutdown(context.Context) {
+	if t.setPercent {
+		debug.SetGCPercent(t.percent)
+	}
+	if t.setLimit {
+		debug.SetMemoryLimit(t.limit)
+	}
+}
+
+func readMetric(name string) uint64 {
+	samples := []metrics.Sample{{Name: name}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+
+	return samples[0].Value.Uint64()
+}
@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gctune_test
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/gctune"
+)
+
+func TestModule(t *testing.T) {
+
+	t.Run("tuning", func(t *testing.T) {
+
+		percent := debug.SetGCPercent(-1)
+		debug.SetGCPercent(percent)
+		limit := debug.SetMemoryLimit(-1)
+
+		app, err := chariot.New(
+			chariot.WithComponents(&gctune.Config{Percent: 50, MemoryLimit: 1 << 30}),
+			gctune.Module(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var tuner *gctune.Tuner
+		if !app.Retrieve(&tuner) {
+			t.FailNow()
+		}
+		switch {
+		case tuner.Percent() != 50:
+			t.Fatal(tuner.Percent())
+		case tuner.MemoryLimit() != 1<<30:
+			t.Fatal(tuner.MemoryLimit())
+		case tuner.String() != "gogc=50 gomemlimit=1073741824":
+			t.Fatal(tuner)
+		}
+
+		app.Shutdown()
+		if tuner.Percent() != percent || tuner.MemoryLimit() != limit {
+			t.Fatal(tuner)
+		}
+	})
+
+	t.Run("negative-limit", func(t *testing.T) {
+
+		if _, err := gctune.New(gctune.Config{MemoryLimit: -1}); err == nil {
+			t.FailNow()
+		}
+	})
+}
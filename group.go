@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AppGroup is a composite of semi-independent apps hosted by a single process, e.g. an admin plane
+// and a data plane, run and shut down as a whole.
+type AppGroup struct {
+	apps []App
+}
+
+// Group makes a composite of the apps.
+func Group(apps ...App) AppGroup {
+	return AppGroup{apps: apps}
+}
+
+// Run runs the apps concurrently with the options given. The first app to fail cancels the
+// context of the runners of the others, and the method waits till all of them finish. Errors
+// returned by the apps are aggregated, the one that triggered the event at the head of the list,
+// each prefixed with the index of the app in the group. In case there was no error the method
+// returns nil.
+func (g AppGroup) Run(funcOptions ...RunOption) error {
+	var options options
+	for _, option := range funcOptions {
+		option(&options)
+	}
+
+	ctx := options.runCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	funcOptions = append(funcOptions[:len(funcOptions):len(funcOptions)], WithRunContext(ctx))
+
+	runErrors := make(chan error, len(g.apps))
+	for i, app := range g.apps {
+		go func(i int, app App) {
+			if err := app.Run(funcOptions...); err != nil {
+				runErrors <- fmt.Errorf("app #%d: %w", i, err)
+
+				return
+			}
+			runErrors <- nil
+		}(i, app)
+	}
+
+	var runErrs []error
+	for range g.apps {
+		if err := <-runErrors; err != nil {
+			cancel()
+			runErrs = append(runErrs, err)
+		}
+	}
+	if len(runErrs) == 0 {
+		return nil
+	}
+
+	return errors.Join(runErrs...)
+}
+
+// Shutdown shuts down the apps in the reverse order they were grouped in with the options given.
+func (g AppGroup) Shutdown(funcOptions ...ShutdownOption) {
+	for i := len(g.apps) - 1; i >= 0; i-- {
+		g.apps[i].Shutdown(funcOptions...)
+	}
+}
@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestGroup(t *testing.T) {
+
+	t.Run("failure", func(t *testing.T) {
+
+		expected := errors.New("failure")
+
+		var a A
+		a.mocks.Run = func(ctx context.Context) error {
+
+			<-ctx.Done()
+
+			return nil
+		}
+
+		var b B
+		b.mocks.Run = func(context.Context) error {
+
+			return expected
+		}
+
+		app1, err := chariot.New(chariot.WithComponents(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app2, err := chariot.New(chariot.WithComponents(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		group := chariot.Group(app1, app2)
+		defer group.Shutdown()
+
+		if err := group.Run(); !errors.Is(err, expected) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+
+		var order []int
+
+		var a A
+		a.mocks.Shutdown = func(context.Context) {
+
+			order = append(order, 1)
+		}
+
+		var b B
+		b.mocks.Shutdown = func(context.Context) {
+
+			order = append(order, 2)
+		}
+
+		app1, err := chariot.New(chariot.WithComponents(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app2, err := chariot.New(chariot.WithComponents(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		group := chariot.Group(app1, app2)
+		if err := group.Run(); err != nil {
+			t.Fatal(err)
+		}
+		group.Shutdown()
+
+		if len(order) != 2 || order[0] != 2 || order[1] != 1 {
+			t.Fatal(order)
+		}
+	})
+}
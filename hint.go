@@ -0,0 +1,136 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxHints is the number of the closest types a hint lists at most.
+const maxHints = 3
+
+// hintMissing logs the closest types of the components of the app to the one that's missing, if
+// resolutions are to be logged (see the DebugToggles type), along with a reminder that pointers
+// and the types they point to are distinct, the most common reason of a miss.
+func (a App) hintMissing(componentType reflect.Type) {
+	if !a.toggles.Verbose() {
+		return
+	}
+
+	var counterpart reflect.Type
+	if componentType.Kind() == reflect.Ptr {
+		counterpart = componentType.Elem()
+	} else {
+		counterpart = reflect.PtrTo(componentType)
+	}
+
+	type candidate struct {
+		componentType reflect.Type
+		distance      int
+	}
+	var candidates []candidate
+	seen := make(map[reflect.Type]bool)
+	for _, registered := range a.componentTypes() {
+		if seen[registered] {
+			continue
+		}
+		seen[registered] = true
+
+		distance := distanceOf(componentType.String(), registered.String())
+		switch {
+		case registered == counterpart:
+			distance = -2
+		case componentType.Kind() == reflect.Interface && registered.Implements(componentType):
+			distance = -1
+		case distance > len(componentType.String())/2:
+			continue
+		}
+		candidates = append(candidates, candidate{registered, distance})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+
+		return candidates[i].componentType.String() < candidates[j].componentType.String()
+	})
+	if len(candidates) > maxHints {
+		candidates = candidates[:maxHints]
+	}
+
+	closest := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		closest = append(closest, fmt.Sprintf("'%s'", candidate.componentType))
+	}
+	hint := "no close types"
+	if len(closest) != 0 {
+		hint = "closest types: " + strings.Join(closest, ", ")
+	}
+
+	reminder := "note that a pointer and the type it points to are distinct types"
+	if len(candidates) != 0 && candidates[0].componentType == counterpart {
+		reminder = fmt.Sprintf(
+			"note that '%s' is provided rather than '%s'; retrieve the former or provide the latter",
+			counterpart,
+			componentType,
+		)
+	}
+
+	a.logf("no component of the type '%s'; %s; %s\n", componentType, hint, reminder)
+}
+
+// distanceOf reports the Levenshtein distance between the strings.
+func distanceOf(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+
+	return a
+}
@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestRunHooks(t *testing.T) {
+
+	t.Run("order", func(t *testing.T) {
+
+		var events []string
+
+		var a A
+		a.mocks.Run = func(context.Context) error {
+
+			events = append(events, "run")
+
+			return nil
+		}
+
+		app, err := chariot.New(chariot.WithComponents(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(
+			chariot.WithBeforeRun(func(ctx context.Context, app chariot.App) error {
+
+				events = append(events, "before")
+
+				return nil
+			}),
+			chariot.WithAfterRun(func(ctx context.Context, app chariot.App, err error) {
+
+				if err != nil || ctx.Err() != nil {
+					t.Fatal(err)
+				}
+				events = append(events, "after")
+			}),
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(events) != 3 || events[0] != "before" || events[1] != "run" || events[2] != "after" {
+			t.Fatal(events)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		expected := errors.New("failure")
+
+		var a A
+		a.mocks.Run = func(context.Context) error {
+
+			t.FailNow()
+
+			return nil
+		}
+
+		app, err := chariot.New(chariot.WithComponents(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var reported error
+		err = app.Run(
+			chariot.WithBeforeRun(func(context.Context, chariot.App) error {
+
+				return expected
+			}),
+			chariot.WithAfterRun(func(_ context.Context, _ chariot.App, err error) {
+
+				reported = err
+			}),
+		)
+		if !errors.Is(err, expected) || reported != err {
+			t.Fatal(err, reported)
+		}
+	})
+}
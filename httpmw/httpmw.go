@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package httpmw ties request-scoped components to HTTP handlers. The middleware opens a scope of
+// an app per request, so handlers retrieve both the application-wide components and the ones
+// specific to the request, e.g. an authenticated principal or a request logger.
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rwyyr/chariot"
+)
+
+type scopeKey struct{}
+
+// Middleware makes a middleware opening a scope of the app per request. The scope is initialized
+// with the request's context, the request itself as a component, and the options provide returns
+// for the request, if provide isn't nil. The scope is shut down once the next handler returns. The
+// middleware responds with 500 Internal Server Error if the scope fails to initialize.
+func Middleware(app chariot.App, provide func(*http.Request) []chariot.Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			options := []chariot.Option{chariot.WithInitContext(r.Context()), chariot.WithComponents(r)}
+			if provide != nil {
+				options = append(options, provide(r)...)
+			}
+
+			scope, err := app.Scope(options...)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+				return
+			}
+			defer scope.Shutdown()
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scopeKey{}, scope)))
+		})
+	}
+}
+
+// FromContext reports the scope the middleware has opened for a request.
+func FromContext(ctx context.Context) (chariot.App, bool) {
+	scope, ok := ctx.Value(scopeKey{}).(chariot.App)
+
+	return scope, ok
+}
+
+// Retrieve retrieves a component of the scope opened for the request. It reports false if the
+// request hasn't passed through the middleware or the component is missing.
+func Retrieve(r *http.Request, ptr interface{}) bool {
+	scope, ok := FromContext(r.Context())
+	if !ok {
+		return false
+	}
+
+	return scope.Retrieve(ptr)
+}
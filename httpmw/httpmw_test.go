@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package httpmw_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/httpmw"
+)
+
+type principal struct {
+	name string
+}
+
+func TestMiddleware(t *testing.T) {
+
+	app, err := chariot.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	t.Run("scope", func(t *testing.T) {
+
+		handler := httpmw.Middleware(app, func(*http.Request) []chariot.Option {
+
+			return []chariot.Option{chariot.With(func(r *http.Request) *principal {
+
+				return &principal{name: r.Header.Get("X-User")}
+			})}
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			var p *principal
+			if !httpmw.Retrieve(r, &p) {
+				t.FailNow()
+			}
+			w.Write([]byte(p.name))
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-User", "gopher")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if body := w.Body.String(); body != "gopher" {
+			t.Fatal(body)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		handler := httpmw.Middleware(app, func(*http.Request) []chariot.Option {
+
+			return []chariot.Option{chariot.With(func(*principal) int {
+
+				return 0
+			})}
+		})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+
+			t.FailNow()
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatal(w.Code)
+		}
+	})
+
+	t.Run("outside", func(t *testing.T) {
+
+		var p *principal
+		if httpmw.Retrieve(httptest.NewRequest(http.MethodGet, "/", nil), &p) {
+			t.FailNow()
+		}
+	})
+}
@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"reflect"
+)
+
+// Identity identifies an initializer (see the WithIdentityContexts option).
+type Identity struct {
+	// Initializer is the name of the initializer.
+	Initializer string
+	// Components are the types of the components the initializer constructs, if any.
+	Components []reflect.Type
+}
+
+type identityKey struct{}
+
+// IdentityFrom reports the identity of the initializer the context was passed to, if any.
+func IdentityFrom(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+
+	return identity, ok
+}
+
+// identify annotates the context among the dependencies of the initializer with its identity if
+// the app is to.
+func (a App) identify(node *node, ins []reflect.Value) []reflect.Value {
+	if !a.identityCtxs {
+		return ins
+	}
+
+	for i, dependencyType := range node.dependencies {
+		if dependencyType != ctxType {
+			continue
+		}
+
+		ctx := withIdentity(ins[i].Interface().(context.Context), node)
+		ins[i] = reflect.ValueOf(&ctx).Elem()
+	}
+
+	return ins
+}
+
+// bound derives the context among the dependencies of the initializer bounded by the timeout of
+// initializers and annotated with its identity, if the app is to (see the WithInitTimeout option).
+// The function returned cancels the context.
+func (a App) bound(node *node, ins []reflect.Value) ([]reflect.Value, func()) {
+	if a.initTimeout <= 0 {
+		return ins, func() {}
+	}
+
+	cancels := make([]func(), 0, 1)
+	for i, dependencyType := range node.dependencies {
+		if dependencyType != ctxType {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(ins[i].Interface().(context.Context), a.initTimeout)
+		ctx = withIdentity(ctx, node)
+		ins[i] = reflect.ValueOf(&ctx).Elem()
+		cancels = append(cancels, cancel)
+	}
+
+	return ins, func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+}
+
+func withIdentity(ctx context.Context, node *node) context.Context {
+	return context.WithValue(ctx, identityKey{}, Identity{
+		Initializer: funcName(node.initializer),
+		Components:  node.signature.components,
+	})
+}
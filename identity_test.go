@@ -0,0 +1,122 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func newIdentifiedC(ctx context.Context) *C {
+
+	identity, ok := chariot.IdentityFrom(ctx)
+	if !ok || !strings.HasSuffix(identity.Initializer, "newIdentifiedC") {
+		panic(identity)
+	}
+	if len(identity.Components) != 1 || identity.Components[0] != reflect.TypeOf(new(C)) {
+		panic(identity)
+	}
+
+	return new(C)
+}
+
+func TestWithIdentityContexts(t *testing.T) {
+
+	t.Run("identified", func(t *testing.T) {
+
+		app, err := chariot.New(
+			chariot.With(newIdentifiedC, chariot.Lazy(func(ctx context.Context) *D {
+
+				if _, ok := chariot.IdentityFrom(ctx); !ok {
+					t.FailNow()
+				}
+
+				return new(D)
+			})),
+			chariot.WithIdentityContexts(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var d *D
+		if !app.Retrieve(&d) {
+			t.FailNow()
+		}
+	})
+
+	t.Run("shared", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func(ctx context.Context) *C {
+
+			if _, ok := chariot.IdentityFrom(ctx); ok {
+				t.FailNow()
+			}
+
+			return new(C)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+	})
+}
+
+func TestWithInitTimeout(t *testing.T) {
+
+	var initCtx context.Context
+
+	app, err := chariot.New(
+		chariot.With(func(ctx context.Context) *C {
+
+			deadline, ok := ctx.Deadline()
+			switch {
+			case !ok:
+				t.Error("no deadline")
+			case time.Until(deadline) > time.Minute:
+				t.Error(deadline)
+			}
+			if _, ok := chariot.IdentityFrom(ctx); !ok {
+				t.Error("no identity")
+			}
+			initCtx = ctx
+
+			return new(C)
+		}),
+		chariot.WithInitTimeout(time.Minute),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	if initCtx.Err() == nil {
+		t.Fatal("the context isn't cancelled")
+	}
+}
@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"errors"
+	"sync"
+)
+
+// WithParallelInits makes inits invoked concurrently by the number of workers rather than one by
+// one, e.g. when many of them are independent registrations. Inits of a phase are still invoked
+// after the ones of the preceding phases (see the Phase function), and after their predecessors
+// (see the After function). Once an init fails, no more inits are invoked, and the errors of the
+// ones invoked are joined.
+func WithParallelInits(workers int) Option {
+	return func(options *options) {
+		options.initWorkers = workers
+	}
+}
+
+// invokeInitsConcurrently invokes the inits of each phase by the workers of the app.
+func (a App) invokeInitsConcurrently(inits []*node) error {
+	for len(inits) > 0 {
+		end := 1
+		for end < len(inits) && inits[end].phase == inits[0].phase {
+			end++
+		}
+
+		if err := a.invokeInitGroup(inits[:end]); err != nil {
+			return err
+		}
+		inits = inits[end:]
+	}
+
+	return nil
+}
+
+// invokeInitGroup invokes the inits concurrently by the workers of the app.
+func (a App) invokeInitGroup(inits []*node) error {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errs    []error
+		pending = make(chan *node)
+		done    = make(map[*node]chan struct{}, len(inits))
+	)
+	for _, init := range inits {
+		done[init] = make(chan struct{})
+	}
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(errs) != 0
+	}
+
+	wg.Add(a.initWorkers)
+	for i := 0; i < a.initWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for init := range pending {
+				// Predecessors are dispatched first, so they're being invoked by other workers.
+				for _, predecessor := range init.after {
+					if predecessorDone, ok := done[predecessor]; ok {
+						<-predecessorDone
+					}
+				}
+				if !failed() {
+					if err := a.invokeInit(init); err != nil {
+						mu.Lock()
+						errs = append(errs, err)
+						mu.Unlock()
+					}
+				}
+				close(done[init])
+			}
+		}()
+	}
+	for _, init := range inits {
+		if failed() {
+			break
+		}
+		pending <- init
+	}
+	close(pending)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
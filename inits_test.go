@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithParallelInits(t *testing.T) {
+
+	t.Run("concurrent", func(t *testing.T) {
+
+		const workers = 4
+
+		var arrived sync.WaitGroup
+		arrived.Add(workers)
+		all := make(chan struct{})
+		go func() {
+
+			arrived.Wait()
+			close(all)
+		}()
+
+		inits := make([]interface{}, workers)
+		for i := range inits {
+			inits[i] = func() error {
+
+				arrived.Done()
+				select {
+				case <-all:
+					return nil
+				case <-time.After(time.Second):
+					return errors.New("inits aren't concurrent")
+				}
+			}
+		}
+
+		app, err := chariot.New(chariot.With(inits...), chariot.WithParallelInits(workers))
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		expected := errors.New("failure")
+
+		_, err := chariot.New(
+			chariot.With(
+				func() error {
+
+					return expected
+				},
+				func() error {
+
+					return nil
+				},
+			),
+			chariot.WithParallelInits(2),
+		)
+		if !errors.Is(err, expected) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Instance describes the instance of a module a scope was made for (see the WithInstances option).
+// Every such scope is provided with the component.
+type Instance struct {
+	// Name is the name of the set of instances.
+	Name string
+	// Index is the index of the element in the list.
+	Index int
+	// Element is the element of the list.
+	Element interface{}
+}
+
+// instanceSet is a module to be instantiated once per element of a list.
+type instanceSet struct {
+	name     string
+	elements []interface{}
+	module   func(interface{}) Module
+}
+
+// instanceApp chains the lifecycle of an instance to the one of the app it was made a scope of.
+type instanceApp struct {
+	embeddedApp
+	name string
+}
+
+// WithInstances instantiates the module once per element of the list, e.g. a consumer per
+// configured topic, sidestepping the rule of distinct types. Each instance is a scope of the app
+// (see the Scope method) made once the app is initialized, so the components of the instance are
+// able to depend on the ones of the app and are told apart from the ones of its siblings; the
+// instance is also provided with the Instance component. The instances are retrieved by the name
+// and their index (see the Instance method). The lifecycles of the instances are chained to the
+// one of the app: they're run as runners of the app, and shut down before all its shutdowners. A
+// value that isn't a slice or an array, or a name used more than once, causes an error.
+func WithInstances(
+	name string,
+	elements interface{},
+	module func(element interface{}) Module,
+) Option {
+	site := callSite()
+
+	return func(options *options) {
+		list := reflect.ValueOf(elements)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			options.errs = append(options.errs, fmt.Errorf(
+				"instances '%s' at %s are of a %T list, expected a slice or an array",
+				name,
+				site,
+				elements,
+			))
+
+			return
+		}
+		for _, set := range options.instances {
+			if set.name == name {
+				options.errs = append(options.errs, fmt.Errorf(
+					"instances '%s' at %s are named the same as others",
+					name,
+					site,
+				))
+
+				return
+			}
+		}
+
+		set := instanceSet{
+			name:     name,
+			elements: make([]interface{}, list.Len()),
+			module:   module,
+		}
+		for i := range set.elements {
+			set.elements[i] = list.Index(i).Interface()
+		}
+		options.instances = append(options.instances, set)
+	}
+}
+
+// Instance reports the scope made for the element of the list at the index by the WithInstances
+// option of the name.
+func (a App) Instance(name string, index int) (App, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	instances := a.instances[name]
+	if index < 0 || index >= len(instances) {
+		return App{}, false
+	}
+
+	return instances[index], true
+}
+
+// Instances reports the number of the instances of the name (see the WithInstances option).
+func (a App) Instances(name string) int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return len(a.instances[name])
+}
+
+// Name names the instance.
+func (i instanceApp) Name() string {
+	return i.name
+}
+
+// instantiate makes the instances of the sets, and chains their lifecycles to the one of the app,
+// the instances placed at the tail of the shutdowners to be shut down first. An instance failing
+// to initialize shuts the ones made before it down.
+func (a App) instantiate(sets []instanceSet) error {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	instances := make(map[string][]App, len(sets))
+	var made []App
+	for _, set := range sets {
+		for i, element := range set.elements {
+			instance := Instance{
+				Name:    set.name,
+				Index:   i,
+				Element: element,
+			}
+			scope, err := a.Scope(set.module(element), WithComponents(instance))
+			if err != nil {
+				for j := len(made) - 1; j >= 0; j-- {
+					made[j].Shutdown()
+				}
+
+				return fmt.Errorf("instance #%d of '%s': %w", i, set.name, err)
+			}
+			instances[set.name] = append(instances[set.name], scope)
+			made = append(made, scope)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.instances = instances
+	for _, set := range sets {
+		for i, scope := range instances[set.name] {
+			instance := instanceApp{
+				embeddedApp: embeddedApp{scope},
+				name:        fmt.Sprintf("instance #%d of '%s'", i, set.name),
+			}
+			runner := &managedRunner{Runner: instance}
+			a.runners = append(a.runners, runner)
+			a.shutdowners = append(a.shutdowners, runnerShutdowner{Shutdowner: instance, runner: runner})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+type instanceShutdowner func(context.Context)
+
+func (s instanceShutdowner) Shutdown(ctx context.Context) {
+
+	s(ctx)
+}
+
+func TestWithInstances(t *testing.T) {
+
+	t.Run("instances", func(t *testing.T) {
+
+		var (
+			mu       sync.Mutex
+			consumed []string
+			shutdown []string
+		)
+		consumer := func(element interface{}) chariot.Module {
+
+			return chariot.With(
+				func(*C, chariot.Instance) namedRunner {
+
+					return func(context.Context) error {
+
+						mu.Lock()
+						defer mu.Unlock()
+
+						consumed = append(consumed, element.(string))
+
+						return nil
+					}
+				},
+				func(instance chariot.Instance) instanceShutdowner {
+
+					return func(context.Context) {
+
+						shutdown = append(shutdown, instance.Element.(string))
+					}
+				},
+			)
+		}
+
+		app, err := chariot.New(
+			chariot.With(func() *C {
+
+				return new(C)
+			}, func() chariot.FuncShutdowner {
+
+				return func(context.Context) {
+
+					shutdown = append(shutdown, "app")
+				}
+			}),
+			chariot.WithInstances("consumers", []string{"orders", "payments"}, consumer),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count := app.Instances("consumers"); count != 2 {
+			t.Fatal(count)
+		}
+		instance, ok := app.Instance("consumers", 1)
+		if !ok {
+			t.FailNow()
+		}
+		var described chariot.Instance
+		if !instance.Retrieve(&described) || described.Index != 1 || described.Element != "payments" {
+			t.Fatal(described)
+		}
+		if _, ok := app.Instance("consumers", 2); ok {
+			t.FailNow()
+		}
+
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(consumed)
+		if !reflect.DeepEqual(consumed, []string{"orders", "payments"}) {
+			t.Fatal(consumed)
+		}
+
+		app.Shutdown()
+		if !reflect.DeepEqual(shutdown, []string{"payments", "orders", "app"}) {
+			t.Fatal(shutdown)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		testErr := errors.New("test")
+		module := func(element interface{}) chariot.Module {
+
+			return chariot.With(func() (*C, error) {
+
+				if element == 2 {
+					return nil, testErr
+				}
+
+				return new(C), nil
+			})
+		}
+		_, err := chariot.New(chariot.WithInstances("consumers", []int{1, 2}, module))
+		if !errors.Is(err, testErr) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		module := func(interface{}) chariot.Module {
+
+			return chariot.With()
+		}
+		if _, err := chariot.New(chariot.WithInstances("consumers", 1, module)); err == nil {
+			t.FailNow()
+		}
+		_, err := chariot.New(
+			chariot.WithInstances("consumers", []int{1}, module),
+			chariot.WithInstances("consumers", []int{2}, module),
+		)
+		if err == nil {
+			t.FailNow()
+		}
+	})
+}
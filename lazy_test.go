@@ -0,0 +1,183 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestLazy(t *testing.T) {
+
+	t.Run("on-demand", func(t *testing.T) {
+
+		key := new(struct{})
+
+		var calls int
+
+		app, err := chariot.New(chariot.With(
+			chariot.Lazy(func(ctx context.Context, b *B) *A {
+
+				calls++
+
+				if value := ctx.Value(key); value != key {
+					t.Fatal(value)
+				}
+
+				return new(A)
+			}),
+			chariot.Lazy(func() *B {
+
+				return new(B)
+			}),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if calls != 0 {
+			t.Fatal(calls)
+		}
+
+		ctx := context.WithValue(context.Background(), key, key)
+
+		var a1, a2 *A
+		switch {
+		case app.RetrieveCtx(ctx, &a1) != nil:
+			t.FailNow()
+		case app.RetrieveCtx(ctx, &a2) != nil:
+			t.FailNow()
+		case a1 != a2:
+			t.FailNow()
+		case calls != 1:
+			t.Fatal(calls)
+		}
+	})
+
+	t.Run("eager-dependent", func(t *testing.T) {
+
+		var called bool
+
+		app, err := chariot.New(chariot.With(
+			chariot.Lazy(func() *A {
+
+				called = true
+
+				return new(A)
+			}),
+			func(*A) *B {
+
+				return new(B)
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if !called {
+			t.FailNow()
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+
+		testErr := errors.New("test error")
+
+		app, err := chariot.New(chariot.With(
+			chariot.Lazy(func() (*A, error) {
+
+				return nil, testErr
+			}),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var a *A
+		if err := app.RetrieveCtx(context.Background(), &a); !errors.Is(err, testErr) {
+			t.Fatal(err)
+		}
+		if app.Retrieve(&a) {
+			t.FailNow()
+		}
+	})
+
+	t.Run("chained", func(t *testing.T) {
+
+		var app chariot.App
+
+		app, err := chariot.New(chariot.With(
+			chariot.Lazy(func() (*A, error) {
+
+				var b *B
+				if err := app.RetrieveCtx(context.Background(), &b); err != nil {
+					return nil, err
+				}
+
+				return new(A), nil
+			}),
+			chariot.Lazy(func() *B {
+
+				return new(B)
+			}),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		retrieved := make(chan error, 1)
+		go func() {
+
+			var a *A
+			retrieved <- app.RetrieveCtx(context.Background(), &a)
+		}()
+
+		select {
+		case err := <-retrieved:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.FailNow()
+		}
+	})
+
+	t.Run("lazy-init", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(chariot.Lazy(func() {})))
+		if err != nil {
+			return
+		}
+		defer app.Shutdown()
+
+		t.FailNow()
+	})
+}
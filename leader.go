@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import "context"
+
+// Elector stands for a leader election backend, e.g. a lease in a distributed store, that a
+// LeaderGated runner campaigns for leadership with.
+type Elector interface {
+	// Campaign blocks till either leadership is acquired or the context is cancelled. A channel
+	// that's closed once leadership is lost is returned upon acquisition; a nil one means
+	// leadership is held till Resign is called.
+	Campaign(context.Context) (<-chan struct{}, error)
+	// Resign gives up leadership.
+	Resign(context.Context) error
+}
+
+// leaderGated is a runner executing only while leadership is held.
+type leaderGated struct {
+	runner  Runner
+	elector Elector
+}
+
+// LeaderGated wraps a runner so it only runs while leadership is held by the elector, e.g. a
+// singleton background job in a replicated deployment. The runner is run once leadership is
+// acquired, and its context is cancelled if leadership is lost, in which case the wrapper
+// campaigns again to resume the runner afterwards. The wrapper returns once the runner returns
+// while being the leader, the context is cancelled, or the elector fails.
+func LeaderGated(runner Runner, elector Elector) Runner {
+	return leaderGated{runner: runner, elector: elector}
+}
+
+// Run runs the runner while leadership is held.
+func (l leaderGated) Run(ctx context.Context) error {
+	for {
+		lost, err := l.elector.Campaign(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		runCtx, cancel := context.WithCancel(ctx)
+		stopped := make(chan struct{})
+		go func() {
+			select {
+			case <-lost:
+				cancel()
+			case <-stopped:
+			}
+		}()
+
+		err = l.runner.Run(runCtx)
+		close(stopped)
+		cancel()
+
+		select {
+		case <-lost:
+			if ctx.Err() == nil {
+				continue
+			}
+		default:
+		}
+
+		if resignErr := l.elector.Resign(context.WithoutCancel(ctx)); resignErr != nil && err == nil {
+			err = resignErr
+		}
+
+		return err
+	}
+}
+
+// Name names the wrapper after the runner.
+func (l leaderGated) Name() string {
+	return nameOf(l.runner)
+}
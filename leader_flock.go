@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !windows && !js && !wasip1 && !plan9
+// +build !windows,!js,!wasip1,!plan9
+
+package chariot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// defaultElectorPoll is the interval a campaign of a file elector retries at if none is given.
+const defaultElectorPoll = time.Second
+
+// FileElector is an Elector electing a single leader among processes sharing a file system by
+// means of an advisory lock of a file.
+type FileElector struct {
+	path string
+	poll time.Duration
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileElector makes an elector locking the file at the path, which is created if it doesn't
+// exist. A campaign retries to acquire the lock at the interval given, or every second if the
+// interval isn't positive.
+func NewFileElector(path string, poll time.Duration) *FileElector {
+	if poll <= 0 {
+		poll = defaultElectorPoll
+	}
+
+	return &FileElector{
+		path: path,
+		poll: poll,
+	}
+}
+
+// Campaign acquires the lock of the file. Leadership is held till Resign is called.
+func (e *FileElector) Campaign(ctx context.Context) (<-chan struct{}, error) {
+	file, err := os.OpenFile(e.path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(e.poll)
+	defer ticker.Stop()
+
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			file.Close()
+
+			return nil, err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			file.Close()
+
+			return nil, ctx.Err()
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.file = file
+
+	return nil, nil
+}
+
+// Resign releases the lock of the file.
+func (e *FileElector) Resign(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.file == nil {
+		return nil
+	}
+
+	err := syscall.Flock(int(e.file.Fd()), syscall.LOCK_UN)
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	e.file = nil
+
+	return err
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !windows && !js && !wasip1 && !plan9
+// +build !windows,!js,!wasip1,!plan9
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestFileElector(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "leader")
+	leader := chariot.NewFileElector(path, time.Millisecond)
+	follower := chariot.NewFileElector(path, time.Millisecond)
+
+	if _, err := leader.Campaign(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := follower.Campaign(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+
+	if err := leader.Resign(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := follower.Campaign(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := follower.Resign(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("default-poll", func(t *testing.T) {
+
+		if _, err := leader.Campaign(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		defer leader.Resign(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		follower := chariot.NewFileElector(path, 0)
+		if _, err := follower.Campaign(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatal(err)
+		}
+	})
+}
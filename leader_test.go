@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+type elector struct {
+	grants chan chan struct{}
+}
+
+func (e elector) Campaign(ctx context.Context) (<-chan struct{}, error) {
+
+	select {
+	case lost := <-e.grants:
+		return lost, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (elector) Resign(context.Context) error {
+
+	return nil
+}
+
+func TestLeaderGated(t *testing.T) {
+
+	t.Run("resume", func(t *testing.T) {
+
+		expected := errors.New("done")
+		e := elector{grants: make(chan chan struct{})}
+
+		var runs int
+		runner := chariot.FuncRunner(func(ctx context.Context) error {
+
+			runs++
+			if runs == 1 {
+				<-ctx.Done()
+
+				return nil
+			}
+
+			return expected
+		})
+
+		exited := make(chan error)
+		go func() {
+
+			exited <- chariot.LeaderGated(runner, e).Run(context.Background())
+		}()
+
+		lost := make(chan struct{})
+		e.grants <- lost
+		close(lost)
+		e.grants <- nil
+
+		if err := <-exited; err != expected || runs != 2 {
+			t.Fatal(err, runs)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		runner := chariot.FuncRunner(func(context.Context) error {
+
+			t.FailNow()
+
+			return nil
+		})
+		if err := chariot.LeaderGated(runner, elector{}).Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
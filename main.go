@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+)
+
+// mainShutdownTimeout bounds the shutdown of an app run by Main.
+const mainShutdownTimeout = 10 * time.Second
+
+// Main standardizes the last lines of a main function: it initializes an app with the function
+// given, runs it with the options, shuts it down, and exits the process. The exit status is 0 if
+// the app runs to completion, the one the ExitCode function maps the error to if it fails to
+// initialize or run, and 2 if the function or the app's methods panic. The panic is recovered and
+// reported, and the app is shut down nonetheless. The shutdown is bounded by a timeout of 10
+// seconds. Note that panics of runners can't be recovered, as they happen in goroutines of their
+// own.
+func Main(initialize func() (App, error), funcOptions ...RunOption) {
+	os.Exit(runMain(initialize, funcOptions))
+}
+
+func runMain(initialize func() (App, error), funcOptions []RunOption) (code int) {
+	var app App
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			fmt.Fprintf(os.Stderr, "panic: %v\n\n%s", recovered, debug.Stack())
+			code = 2
+		}
+		if app.Valid() {
+			ctx, cancel := context.WithTimeout(context.Background(), mainShutdownTimeout)
+			defer cancel()
+			app.Shutdown(WithShutdownContext(ctx))
+		}
+	}()
+
+	app, err := initialize()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize: %s\n", err)
+
+		return ExitCode(err)
+	}
+
+	if err := app.Run(funcOptions...); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to run: %s\n", err)
+
+		return ExitCode(err)
+	}
+
+	return 0
+}
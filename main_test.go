@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+const mainScenarioEnv = "CHARIOT_TEST_MAIN_SCENARIO"
+
+func TestMainFunc(t *testing.T) {
+
+	if scenario, ok := os.LookupEnv(mainScenarioEnv); ok {
+		chariot.Main(func() (chariot.App, error) {
+
+			var (
+				a A
+				b B
+			)
+			switch scenario {
+			case "failure":
+				return chariot.App{}, errors.New("failure")
+			case "runner":
+				a.mocks.Run = func(context.Context) error {
+
+					return errors.New("failure")
+				}
+				b.mocks.Run = func(ctx context.Context) error {
+
+					<-ctx.Done()
+
+					return ctx.Err()
+				}
+			case "panic":
+				panic("panic")
+			case "shutdown":
+				a.mocks.Shutdown = func(context.Context) {
+
+					os.Exit(3)
+				}
+			}
+
+			return chariot.New(chariot.WithComponents(a, b))
+		})
+	}
+
+	exitCode := func(scenario string) int {
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestMainFunc$")
+		cmd.Env = append(os.Environ(), mainScenarioEnv+"="+scenario)
+		if err := cmd.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				return exitErr.ExitCode()
+			}
+			t.Fatal(err)
+		}
+
+		return 0
+	}
+
+	for scenario, expected := range map[string]int{
+		"success":  0,
+		"failure":  1,
+		"runner":   1,
+		"panic":    2,
+		"shutdown": 3,
+	} {
+		if code := exitCode(scenario); code != expected {
+			t.Fatal(scenario, code)
+		}
+	}
+}
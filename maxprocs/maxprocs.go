@@ -0,0 +1,190 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package maxprocs tunes GOMAXPROCS to the CPU quota of the cgroup of the process, so that a
+// containerized app doesn't run more threads than the CPUs it's allowed to use and get throttled.
+// Both cgroup v1 and v2 are supported; the quota isn't looked up on other platforms, where the
+// files are missing. GOMAXPROCS set in the environment takes precedence over the quota.
+package maxprocs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/rwyyr/chariot"
+)
+
+const defaultRoot = "/sys/fs/cgroup"
+
+type (
+	// MaxProcs is a Shutdowner-conformant component tuning GOMAXPROCS once made, and restoring the
+	// previous value once shut down.
+	MaxProcs struct {
+		previous int
+		procs    int
+		quota    float64
+		tuned    bool
+	}
+
+	// Option stands for an option of the tuning.
+	Option func(*options)
+
+	options struct {
+		root    string
+		minimum int
+	}
+)
+
+// Module provides the MaxProcs component tuning GOMAXPROCS to an app. A constructor depending on
+// the component is invoked with GOMAXPROCS already tuned.
+func Module(funcOptions ...Option) chariot.Module {
+	return chariot.With(func() (*MaxProcs, error) {
+		return New(funcOptions...)
+	})
+}
+
+// WithRoot provides a replacement to the default root the cgroup filesystem is mounted at,
+// /sys/fs/cgroup.
+func WithRoot(root string) Option {
+	return func(options *options) {
+		options.root = root
+	}
+}
+
+// WithMinimum provides a replacement to the default minimum of 1 GOMAXPROCS is tuned to, e.g. for
+// a quota of a fraction of a CPU. A minimum below 1 is deemed 1.
+func WithMinimum(minimum int) Option {
+	return func(options *options) {
+		options.minimum = minimum
+		if options.minimum < 1 {
+			options.minimum = 1
+		}
+	}
+}
+
+// New tunes GOMAXPROCS to the CPU quota, rounded down, yet neither below the minimum nor above the
+// number of CPUs. GOMAXPROCS is left intact if it's set in the environment or there's no quota. An
+// error is returned if the quota is malformed.
+func New(funcOptions ...Option) (*MaxProcs, error) {
+	options := options{
+		root:    defaultRoot,
+		minimum: 1,
+	}
+	for _, option := range funcOptions {
+		option(&options)
+	}
+
+	previous := runtime.GOMAXPROCS(0)
+	maxProcs := MaxProcs{
+		previous: previous,
+		procs:    previous,
+	}
+	if os.Getenv("GOMAXPROCS") != "" {
+		return &maxProcs, nil
+	}
+
+	quota, ok, err := readQuota(options.root)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &maxProcs, nil
+	}
+
+	procs := int(math.Floor(quota))
+	if procs < options.minimum {
+		procs = options.minimum
+	}
+	if cpus := runtime.NumCPU(); procs > cpus {
+		procs = cpus
+	}
+	runtime.GOMAXPROCS(procs)
+	maxProcs.procs, maxProcs.quota, maxProcs.tuned = procs, quota, true
+
+	return &maxProcs, nil
+}
+
+// Procs reports GOMAXPROCS as tuned.
+func (m *MaxProcs) Procs() int {
+	return m.procs
+}
+
+// Quota reports the CPU quota in CPUs, if GOMAXPROCS was tuned to one.
+func (m *MaxProcs) Quota() (float64, bool) {
+	return m.quota, m.tuned
+}
+
+// Shutdown restores the previous GOMAXPROCS, if it was tuned.
+func (m *MaxProcs) Shutdown(context.Context) {
+	if m.tuned {
+		runtime.GOMAXPROCS(m.previous)
+	}
+}
+
+// readQuota reads the CPU quota of the cgroup, trying v2 first.
+func readQuota(root string) (float64, bool, error) {
+	if content, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(content))
+		if len(fields) != 2 {
+			return 0, false, fmt.Errorf("malformed cpu.max: %q", content)
+		}
+		if fields[0] == "max" {
+			return 0, false, nil
+		}
+
+		return parseQuota(fields[0], fields[1])
+	}
+
+	quota, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false, nil
+	}
+	period, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false, nil
+	}
+
+	return parseQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+// parseQuota parses the quota and the period in microseconds; a negative quota means none.
+func parseQuota(rawQuota, rawPeriod string) (float64, bool, error) {
+	quota, err := strconv.ParseInt(rawQuota, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("malformed CPU quota: %w", err)
+	}
+	period, err := strconv.ParseInt(rawPeriod, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("malformed CPU period: %w", err)
+	}
+	if quota < 0 || period <= 0 {
+		return 0, false, nil
+	}
+
+	return float64(quota) / float64(period), true, nil
+}
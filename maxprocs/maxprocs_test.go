@@ -0,0 +1,138 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package maxprocs_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/maxprocs"
+)
+
+func writeFile(t *testing.T, path, content string) {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestModule(t *testing.T) {
+
+	t.Setenv("GOMAXPROCS", "")
+	previous := runtime.GOMAXPROCS(0)
+
+	t.Run("v2", func(t *testing.T) {
+
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "cpu.max"), "150000 100000\n")
+
+		app, err := chariot.New(maxprocs.Module(maxprocs.WithRoot(root)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var maxProcs *maxprocs.MaxProcs
+		if !app.Retrieve(&maxProcs) {
+			t.FailNow()
+		}
+		if quota, ok := maxProcs.Quota(); !ok || quota != 1.5 {
+			t.Fatal(quota)
+		}
+		if procs := runtime.GOMAXPROCS(0); procs != 1 || maxProcs.Procs() != 1 {
+			t.Fatal(procs)
+		}
+
+		app.Shutdown()
+		if procs := runtime.GOMAXPROCS(0); procs != previous {
+			t.Fatal(procs)
+		}
+	})
+
+	t.Run("v1", func(t *testing.T) {
+
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "cpu", "cpu.cfs_quota_us"), "400000\n")
+		writeFile(t, filepath.Join(root, "cpu", "cpu.cfs_period_us"), "100000\n")
+
+		maxProcs, err := maxprocs.New(maxprocs.WithRoot(root), maxprocs.WithMinimum(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer maxProcs.Shutdown(context.Background())
+
+		expected := 4
+		if cpus := runtime.NumCPU(); cpus < expected {
+			expected = cpus
+		}
+		if procs := maxProcs.Procs(); procs != expected {
+			t.Fatal(procs)
+		}
+	})
+
+	t.Run("zero-minimum", func(t *testing.T) {
+
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "cpu.max"), "50000 100000\n")
+
+		maxProcs, err := maxprocs.New(maxprocs.WithRoot(root), maxprocs.WithMinimum(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer maxProcs.Shutdown(context.Background())
+
+		if procs := runtime.GOMAXPROCS(0); procs != 1 || maxProcs.Procs() != 1 {
+			t.Fatal(procs, maxProcs.Procs())
+		}
+	})
+
+	t.Run("no-quota", func(t *testing.T) {
+
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "cpu.max"), "max 100000\n")
+
+		maxProcs, err := maxprocs.New(maxprocs.WithRoot(root))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := maxProcs.Quota(); ok || maxProcs.Procs() != previous {
+			t.Fatal(maxProcs.Procs())
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "cpu.max"), "lots 100000\n")
+
+		if _, err := maxprocs.New(maxprocs.WithRoot(root)); err == nil {
+			t.FailNow()
+		}
+	})
+}
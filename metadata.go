@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// WithMetadata provides labels identifying an app, e.g. the name of the service, its version and
+// the environment it's deployed to, so that tooling identifies the app consistently: the labels
+// are propagated into the events reported to observers (see the RegisterObserver function), the
+// logs of the app, its run reports and its console. The labels of several options are merged, a
+// later value of the same key taking precedence. A scope inherits the labels of its parent along
+// with its own.
+func WithMetadata(metadata map[string]string) Option {
+	return func(options *options) {
+		if options.metadata == nil {
+			options.metadata = make(map[string]string, len(metadata))
+		}
+		for key, value := range metadata {
+			options.metadata[key] = value
+		}
+	}
+}
+
+// Metadata reports the labels of the app (see the WithMetadata option).
+func (a App) Metadata() map[string]string {
+	return copyMetadata(a.metadata)
+}
+
+// mergeMetadata merges the labels of the parent, if any, and the given ones. Nil is reported if
+// there are no labels.
+func mergeMetadata(parent App, metadata map[string]string) map[string]string {
+	var merged map[string]string
+	if parent.Valid() && len(parent.metadata) != 0 {
+		merged = copyMetadata(parent.metadata)
+	}
+	for key, value := range metadata {
+		if merged == nil {
+			merged = make(map[string]string, len(metadata))
+		}
+		merged[key] = value
+	}
+
+	return merged
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+// formatMetadata formats the labels as space-separated key=value pairs ordered by key.
+func formatMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, " ")
+}
+
+// logf logs a message of the app, prefixed with its labels, if any.
+func (a App) logf(format string, args ...interface{}) {
+	prefix := "chariot: "
+	if len(a.metadata) != 0 {
+		prefix = fmt.Sprintf("chariot [%s]: ", formatMetadata(a.metadata))
+	}
+	log.Printf("%s"+format, append([]interface{}{prefix}, args...)...)
+}
+
+// logError is the default handler of the errors of the app.
+func (a App) logError(_ context.Context, err error) {
+	a.logf("%s\n", err)
+}
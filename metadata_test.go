@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithMetadata(t *testing.T) {
+
+	t.Run("propagation", func(t *testing.T) {
+
+		metadata := map[string]string{"service": "api", "env": "test"}
+		observer := new(recordingObserver)
+		app, err := chariot.New(
+			chariot.WithMetadata(map[string]string{"service": "api", "env": "dev"}),
+			chariot.WithMetadata(map[string]string{"env": "test"}),
+			chariot.WithObserver(observer),
+			chariot.With(func() namedRunner {
+
+				return func(context.Context) error {
+
+					return nil
+				}
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if !reflect.DeepEqual(app.Metadata(), metadata) {
+			t.Fatal(app.Metadata())
+		}
+		report := app.RunReport()
+		if !reflect.DeepEqual(report.Metadata, metadata) {
+			t.Fatal(report.Metadata)
+		}
+		for _, event := range observer.events {
+			var labels map[string]string
+			switch event := event.(type) {
+			case chariot.InitEvent:
+				labels = event.Metadata
+			case chariot.RunnerEvent:
+				labels = event.Metadata
+			}
+			if !reflect.DeepEqual(labels, metadata) {
+				t.Fatal(event)
+			}
+		}
+	})
+
+	t.Run("scope", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.WithMetadata(map[string]string{"service": "api"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		scope, err := app.Scope(chariot.WithMetadata(map[string]string{"request": "42"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer scope.Shutdown()
+
+		if metadata := scope.Metadata(); !reflect.DeepEqual(metadata, map[string]string{
+			"service": "api",
+			"request": "42",
+		}) {
+			t.Fatal(metadata)
+		}
+	})
+
+	t.Run("logs", func(t *testing.T) {
+
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer log.SetOutput(os.Stderr)
+
+		app, err := chariot.New(
+			chariot.WithMetadata(map[string]string{"service": "api", "load": "100%"}),
+			chariot.With(func() chariot.FuncShutdowner {
+
+				return func(context.Context) {
+
+					time.Sleep(50 * time.Millisecond)
+				}
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown(chariot.WithShutdownerTimeout(time.Millisecond))
+
+		if !strings.Contains(logs.String(), "chariot [load=100% service=api]: ") {
+			t.Fatal(logs.String())
+		}
+	})
+}
@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// ContainerMetrics are counters describing the components of an app, e.g. for platform teams to
+// export uniform telemetry of the apps across services. Inherited components (see the Scope method)
+// and the ones an app is prepackaged with aren't accounted for.
+type ContainerMetrics struct {
+	// Registered is the number of components the app provides.
+	Registered int
+	// Constructed is the number of components constructed so far.
+	Constructed int
+	// Pending is the number of components of lazy constructors not constructed yet (see the Lazy
+	// function).
+	Pending int
+	// Overridden is the number of times components have been replaced (see the Swap method).
+	Overridden int
+	// Unused is the number of components neither depended on nor retrieved so far.
+	Unused int
+	// Retrievals is the number of retrievals of components performed via the app.
+	Retrievals int
+	// Scopes is the number of the app's scopes open at the moment (see the Scope method).
+	Scopes int
+}
+
+// ContainerMetrics reports the metrics of the app's components.
+func (a App) ContainerMetrics() ContainerMetrics {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	metrics := ContainerMetrics{
+		Overridden: int(atomic.LoadInt64(&a.overrides)),
+		Retrievals: int(atomic.LoadInt64(&a.retrievals)),
+		Scopes:     int(atomic.LoadInt64(&a.scopes)),
+	}
+	for componentType, component := range a.components {
+		if isPrepackaged(componentType) {
+			continue
+		}
+
+		metrics.Registered++
+		switch {
+		case component.value.IsValid():
+			metrics.Constructed++
+		case component.node != nil && !component.node.factory:
+			metrics.Pending++
+		}
+		if atomic.LoadInt32(&component.used) == 0 {
+			metrics.Unused++
+		}
+	}
+
+	return metrics
+}
+
+func isPrepackaged(componentType reflect.Type) bool {
+	switch componentType {
+	case ctxType, buildInfoType, signalsType, spawnerType, runContextType, stopTokenType,
+		debugTogglesType, valuesType:
+		return true
+	default:
+		return false
+	}
+}
+
+// markUsed records the component has been either depended on or retrieved.
+func (c *component) markUsed() {
+	atomic.StoreInt32(&c.used, 1)
+}
@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestAppContainerMetrics(t *testing.T) {
+
+	app, err := chariot.New(chariot.With(
+		func() *C {
+
+			return new(C)
+		},
+		func(*C) *D {
+
+			return new(D)
+		},
+		chariot.Lazy(func() *F {
+
+			return new(F)
+		}),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	metrics := app.ContainerMetrics()
+	switch {
+	case metrics.Registered != 3:
+		t.Fatal(metrics)
+	case metrics.Constructed != 2:
+		t.Fatal(metrics)
+	case metrics.Pending != 1:
+		t.Fatal(metrics)
+	case metrics.Unused != 2:
+		t.Fatal(metrics)
+	}
+
+	var f *F
+	if !app.Retrieve(&f) {
+		t.FailNow()
+	}
+	if err := app.Swap(&f); err != nil {
+		t.Fatal(err)
+	}
+	scope, err := app.Scope()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metrics = app.ContainerMetrics()
+	switch {
+	case metrics.Constructed != 3:
+		t.Fatal(metrics)
+	case metrics.Pending != 0:
+		t.Fatal(metrics)
+	case metrics.Unused != 1:
+		t.Fatal(metrics)
+	case metrics.Overridden != 1:
+		t.Fatal(metrics)
+	case metrics.Retrievals != 1:
+		t.Fatal(metrics)
+	case metrics.Scopes != 1:
+		t.Fatal(metrics)
+	}
+
+	scope.Shutdown()
+	if metrics := app.ContainerMetrics(); metrics.Scopes != 0 {
+		t.Fatal(metrics)
+	}
+}
@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+)
+
+type (
+	runnerNameKey     struct{}
+	shutdownerNameKey struct{}
+)
+
+// WithRunMiddleware provides a middleware wrapping every runner of an app, so that cross-cutting
+// concerns, e.g. logging, metrics or tracing, are layered once rather than per component. The
+// middleware is applied to each start of a runner, restarts included; middlewares are applied in
+// the order they were provided in, the first one being the outermost. The name of the runner is
+// available to the middleware from the context (see the RunnerNameFrom function).
+func WithRunMiddleware(middleware func(next FuncRunner) FuncRunner) RunOption {
+	return func(options *options) {
+		options.runMiddleware = append(options.runMiddleware, middleware)
+	}
+}
+
+// RunnerNameFrom reports the name of the runner the context was passed to by a middleware (see the
+// WithRunMiddleware option), if any.
+func RunnerNameFrom(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(runnerNameKey{}).(string)
+
+	return name, ok
+}
+
+// wrapRunner applies the middleware to the runner.
+func wrapRunner(runner Runner, middleware []func(FuncRunner) FuncRunner) FuncRunner {
+	run := FuncRunner(runner.Run)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		run = middleware[i](run)
+	}
+
+	return run
+}
+
+// WithShutdownMiddleware provides a middleware wrapping every shutdowner of an app, symmetrically
+// to the WithRunMiddleware option, so that teardown is timed, logged or otherwise observed
+// consistently without modifying each component. Middlewares are applied in the order they were
+// provided in, the first one being the outermost. A panic escaping a middleware is recovered the
+// same way one escaping a shutdowner is. The name of the shutdowner is available to the middleware
+// from the context (see the ShutdownerNameFrom function).
+func WithShutdownMiddleware(middleware func(next FuncShutdowner) FuncShutdowner) ShutdownOption {
+	return func(options *options) {
+		options.stopMiddleware = append(options.stopMiddleware, middleware)
+	}
+}
+
+// ShutdownerNameFrom reports the name of the shutdowner the context was passed to by a middleware
+// (see the WithShutdownMiddleware option), if any.
+func ShutdownerNameFrom(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(shutdownerNameKey{}).(string)
+
+	return name, ok
+}
+
+// wrapShutdowner applies the middleware to the shutdowner.
+func wrapShutdowner(
+	shutdowner Shutdowner,
+	middleware []func(FuncShutdowner) FuncShutdowner,
+) FuncShutdowner {
+	shutdown := FuncShutdowner(shutdowner.Shutdown)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		shutdown = middleware[i](shutdown)
+	}
+
+	return shutdown
+}
@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithRunMiddleware(t *testing.T) {
+
+	testErr := errors.New("test")
+	app, err := chariot.New(chariot.With(func() namedRunner {
+
+		return func(context.Context) error {
+
+			return testErr
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	var calls []string
+	layer := func(layer string) func(chariot.FuncRunner) chariot.FuncRunner {
+
+		return func(next chariot.FuncRunner) chariot.FuncRunner {
+
+			return func(ctx context.Context) error {
+
+				name, _ := chariot.RunnerNameFrom(ctx)
+				calls = append(calls, layer+" "+name)
+
+				return next(ctx)
+			}
+		}
+	}
+
+	err = app.Run(chariot.WithRunMiddleware(layer("outer")), chariot.WithRunMiddleware(layer("inner")))
+	if !errors.Is(err, testErr) {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(calls, []string{"outer named", "inner named"}) {
+		t.Fatal(calls)
+	}
+}
+
+func TestWithShutdownMiddleware(t *testing.T) {
+
+	var shutdown bool
+	app, err := chariot.New(chariot.With(func() chariot.FuncShutdowner {
+
+		return func(context.Context) {
+
+			shutdown = true
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	layer := func(layer string) func(chariot.FuncShutdowner) chariot.FuncShutdowner {
+
+		return func(next chariot.FuncShutdowner) chariot.FuncShutdowner {
+
+			return func(ctx context.Context) {
+
+				_, ok := chariot.ShutdownerNameFrom(ctx)
+				calls = append(calls, layer+" "+strconv.FormatBool(ok))
+				next(ctx)
+			}
+		}
+	}
+
+	app.Shutdown(
+		chariot.WithShutdownMiddleware(layer("outer")),
+		chariot.WithShutdownMiddleware(layer("inner")),
+	)
+	if !shutdown {
+		t.Fatal("not shut down")
+	}
+	if !reflect.DeepEqual(calls, []string{"outer true", "inner true"}) {
+		t.Fatal(calls)
+	}
+}
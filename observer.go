@@ -0,0 +1,244 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InitEvent describes the invocation of an initializer.
+type InitEvent struct {
+	// Initializer is the name of the initializer.
+	Initializer string
+	// Description is the description of the initializer, if any (see the Describe function).
+	Description string
+	// Components are the types of the components the initializer provides.
+	Components []reflect.Type
+	// Started is the time the initializer was invoked at.
+	Started time.Time
+	// Duration is the time the initializer took.
+	Duration time.Duration
+	// Err is the error the initializer returned, if any.
+	Err error
+	// Metadata are the labels of the app (see the WithMetadata option).
+	Metadata map[string]string
+}
+
+// RunnerEvent describes a transition of a runner to a state.
+type RunnerEvent struct {
+	// Runner is the name of the runner (see the Named interface).
+	Runner string
+	// State is the state the runner transitioned to.
+	State RunnerState
+	// At is the time of the transition.
+	At time.Time
+	// Err is the error the runner returned, if any.
+	Err error
+	// Metadata are the labels of the app (see the WithMetadata option).
+	Metadata map[string]string
+}
+
+// ShutdownEvent describes the invocation of a shutdowner.
+type ShutdownEvent struct {
+	// Shutdowner is the name of the shutdowner (see the Named interface).
+	Shutdowner string
+	// Started is the time the shutdowner was invoked at.
+	Started time.Time
+	// Duration is the time the shutdowner took.
+	Duration time.Duration
+	// Err is the error the shutdowner failed with, if any, e.g. due to a panic.
+	Err error
+	// Metadata are the labels of the app (see the WithMetadata option).
+	Metadata map[string]string
+}
+
+type (
+	// InitObserver is implemented by an observer hooking the initialization of apps.
+	InitObserver interface {
+		ObserveInit(InitEvent)
+	}
+
+	// RunnerObserver is implemented by an observer hooking the runs of apps.
+	RunnerObserver interface {
+		ObserveRunner(RunnerEvent)
+	}
+
+	// ShutdownObserver is implemented by an observer hooking the shutdowns of apps.
+	ShutdownObserver interface {
+		ObserveShutdown(ShutdownEvent)
+	}
+)
+
+// observerSet is the observers of an app sorted by the events they observe.
+type observerSet struct {
+	init     []InitObserver
+	runner   []RunnerObserver
+	shutdown []ShutdownObserver
+	metadata map[string]string
+}
+
+var observerRegistry struct {
+	mu        sync.RWMutex
+	observers map[string]interface{}
+}
+
+// RegisterObserver makes an observer available by the name, the way the database/sql package
+// makes drivers available, so that an integration, e.g. one of an observability vendor, hooks
+// apps without chariot depending on it. An observer implements any of the InitObserver,
+// RunnerObserver and ShutdownObserver interfaces, and observes every app, scopes included,
+// initialized after it's registered. Observers are invoked synchronously and concurrently, so
+// they're to return promptly. If RegisterObserver is called twice with the same name, or if the
+// observer is nil or implements none of the interfaces, it panics.
+func RegisterObserver(name string, observer interface{}) {
+	if err := validateObserver(observer); err != nil {
+		panic(fmt.Sprintf("chariot: observer '%s' %s", name, err))
+	}
+
+	observerRegistry.mu.Lock()
+	defer observerRegistry.mu.Unlock()
+
+	if _, ok := observerRegistry.observers[name]; ok {
+		panic(fmt.Sprintf("chariot: observer '%s' is registered twice", name))
+	}
+	if observerRegistry.observers == nil {
+		observerRegistry.observers = make(map[string]interface{})
+	}
+	observerRegistry.observers[name] = observer
+}
+
+// Observers reports the names of the registered observers in order.
+func Observers() []string {
+	observerRegistry.mu.RLock()
+	defer observerRegistry.mu.RUnlock()
+
+	return sortedKeys(observerRegistry.observers)
+}
+
+// WithObserver makes an app observed by the observer on top of the registered ones (see the
+// RegisterObserver function). An observer that is nil or implements none of the interfaces causes
+// an error.
+func WithObserver(observer interface{}) Option {
+	return func(options *options) {
+		if err := validateObserver(observer); err != nil {
+			options.errs = append(options.errs, fmt.Errorf("observer %w", err))
+
+			return
+		}
+
+		options.observers = append(options.observers, observer)
+	}
+}
+
+func validateObserver(observer interface{}) error {
+	switch observer.(type) {
+	case nil:
+		return errors.New("is nil")
+	case InitObserver, RunnerObserver, ShutdownObserver:
+		return nil
+	default:
+		return fmt.Errorf("of type '%T' implements none of the observer interfaces", observer)
+	}
+}
+
+// newObserverSet sorts the registered observers, in the order of their names, and the ones given
+// by the events they observe. The events are labelled with the metadata.
+func newObserverSet(observers []interface{}, metadata map[string]string) observerSet {
+	observerRegistry.mu.RLock()
+	all := make([]interface{}, 0, len(observerRegistry.observers)+len(observers))
+	for _, name := range sortedKeys(observerRegistry.observers) {
+		all = append(all, observerRegistry.observers[name])
+	}
+	observerRegistry.mu.RUnlock()
+
+	set := observerSet{metadata: metadata}
+	for _, observer := range append(all, observers...) {
+		if observer, ok := observer.(InitObserver); ok {
+			set.init = append(set.init, observer)
+		}
+		if observer, ok := observer.(RunnerObserver); ok {
+			set.runner = append(set.runner, observer)
+		}
+		if observer, ok := observer.(ShutdownObserver); ok {
+			set.shutdown = append(set.shutdown, observer)
+		}
+	}
+
+	return set
+}
+
+func sortedKeys(observers map[string]interface{}) []string {
+	keys := make([]string, 0, len(observers))
+	for key := range observers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// observeInit reports the invocation of an initializer unless it's one generated for a component
+// provided as a value.
+func (s observerSet) observeInit(
+	initializer *node,
+	started time.Time,
+	took time.Duration,
+	err error,
+) {
+	if len(s.init) == 0 || initializer.fromValue {
+		return
+	}
+
+	event := InitEvent{
+		Initializer: funcName(initializer.initializer),
+		Description: initializer.description,
+		Components:  append([]reflect.Type(nil), initializer.signature.components...),
+		Started:     started,
+		Duration:    took,
+		Err:         err,
+		Metadata:    s.metadata,
+	}
+	for _, observer := range s.init {
+		observer.ObserveInit(event)
+	}
+}
+
+// observeRunner reports a transition of a runner.
+func (s observerSet) observeRunner(event RunnerEvent) {
+	event.Metadata = s.metadata
+	for _, observer := range s.runner {
+		observer.ObserveRunner(event)
+	}
+}
+
+// observeShutdown reports the invocation of a shutdowner.
+func (s observerSet) observeShutdown(event ShutdownEvent) {
+	event.Metadata = s.metadata
+	for _, observer := range s.shutdown {
+		observer.ObserveShutdown(event)
+	}
+}
@@ -0,0 +1,181 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []interface{}
+}
+
+func (o *recordingObserver) ObserveInit(event chariot.InitEvent) {
+
+	o.record(event)
+}
+
+func (o *recordingObserver) ObserveRunner(event chariot.RunnerEvent) {
+
+	o.record(event)
+}
+
+func (o *recordingObserver) ObserveShutdown(event chariot.ShutdownEvent) {
+
+	o.record(event)
+}
+
+func (o *recordingObserver) record(event interface{}) {
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.events = append(o.events, event)
+}
+
+func TestWithObserver(t *testing.T) {
+
+	t.Run("events", func(t *testing.T) {
+
+		testErr := errors.New("test")
+		observer := new(recordingObserver)
+		app, err := chariot.New(
+			chariot.WithObserver(observer),
+			chariot.With(chariot.Describe(func() namedRunner {
+
+				return func(context.Context) error {
+
+					return testErr
+				}
+			}, "worker"), func() chariot.FuncShutdowner {
+
+				return func(context.Context) {}
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Run(); !errors.Is(err, testErr) {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+
+		var described, runners, shutdowns int
+		for _, event := range observer.events {
+			switch event := event.(type) {
+			case chariot.InitEvent:
+				if event.Description == "worker" && len(event.Components) == 1 {
+					described++
+				}
+			case chariot.RunnerEvent:
+				runners++
+				if event.Runner != "named" {
+					t.Fatal(event)
+				}
+				if event.State == chariot.RunnerFailed && !errors.Is(event.Err, testErr) {
+					t.Fatal(event)
+				}
+			case chariot.ShutdownEvent:
+				shutdowns++
+			}
+		}
+		if described != 1 || runners != 2 || shutdowns != 1 {
+			t.Fatal(observer.events)
+		}
+	})
+
+	t.Run("generated-constructors", func(t *testing.T) {
+
+		embedded, err := chariot.New(chariot.WithComponents(new(C)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer embedded.Shutdown()
+
+		observer := new(recordingObserver)
+		app, err := chariot.New(
+			chariot.WithObserver(observer),
+			chariot.WithEmbedded(embedded, reflect.TypeOf((*C)(nil))),
+			chariot.WithComponents(new(D)),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var reExported int
+		for _, event := range observer.events {
+			event, ok := event.(chariot.InitEvent)
+			if !ok {
+				continue
+			}
+
+			switch {
+			case len(event.Components) != 1:
+				t.Fatal(event)
+			case event.Components[0] == reflect.TypeOf((*D)(nil)):
+				t.Fatal(event)
+			case event.Components[0] == reflect.TypeOf((*C)(nil)):
+				reExported++
+			}
+		}
+		if reExported != 1 {
+			t.Fatal(observer.events)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		if _, err := chariot.New(chariot.WithObserver(struct{}{})); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("register", func(t *testing.T) {
+
+		chariot.RegisterObserver("test", new(recordingObserver))
+
+		var registered bool
+		for _, name := range chariot.Observers() {
+			registered = registered || name == "test"
+		}
+		if !registered {
+			t.Fatal(chariot.Observers())
+		}
+
+		defer func() {
+
+			if recover() == nil {
+				t.FailNow()
+			}
+		}()
+		chariot.RegisterObserver("test", new(recordingObserver))
+	})
+}
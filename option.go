@@ -24,7 +24,12 @@ package chariot
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"reflect"
+	"runtime"
+	"time"
 )
 
 // Option is an option one can provide to the New function.
@@ -36,35 +41,184 @@ type RunOption func(*options)
 // ShutdownOption is an option that may be passed to the 'App.Shutdown' method.
 type ShutdownOption func(*options)
 
-// With provides initializers.
+// With provides initializers. A value that isn't a function causes an error.
 func With(initializers ...interface{}) Option {
+	site := callSite()
+
 	return func(options *options) {
-		options.initializers = append(options.initializers, initializers...)
+		for i, initializer := range initializers {
+			if err := validateInitializer(initializer); err != nil {
+				options.errs = append(
+					options.errs,
+					fmt.Errorf("initializer #%d passed to With at %s %w", i+1, site, err),
+				)
+
+				continue
+			}
+
+			options.initializers = append(options.initializers, initializer)
+		}
 	}
 }
 
 // WithComponents provides a component as a value, not via a constructor. Note, however, that
 // because of intricacies of interface assignment one can't provide a component of an interface
-// type this way. Resort to using a constructor to bypass the limitation.
+// type this way. Resort to using a constructor to bypass the limitation. A nil value causes an
+// error.
 func WithComponents(components ...interface{}) Option {
+	site := callSite()
+
 	return func(options *options) {
-		options.components = append(options.components, components...)
+		for i, component := range components {
+			if component == nil {
+				options.errs = append(
+					options.errs,
+					fmt.Errorf("component #%d passed to WithComponents at %s is nil", i+1, site),
+				)
+
+				continue
+			}
+
+			options.components = append(options.components, component)
+		}
 	}
 }
 
 // WithSignals provides additional signals to extend the list that controls the behavior of a
-// prepackaged context.
+// prepackaged context. Signals are ignored on platforms lacking them, i.e. js and wasip1, where the
+// context is only cancelled by shutting the app down.
 func WithSignals(signals ...os.Signal) Option {
 	return func(options *options) {
 		options.signals = append(options.signals, signals...)
 	}
 }
 
-// WithContext provides a replacement to a prepackaged context for the duration of the function.
-// Note, however, that the latter is still taken into account even if a replacement is provided.
+// WithInitContext provides a replacement to a prepackaged context for the duration of the
+// initialization of an app, i.e. the one passed to its initializers. Note, however, that the
+// latter is still taken into account even if a replacement is provided.
+func WithInitContext(ctx context.Context) Option {
+	return func(options *options) {
+		options.initCtx = ctx
+	}
+}
+
+// WithContext is an alias of the WithInitContext option.
+//
+// Deprecated: Use WithInitContext, which tells the phase of the context apart from the ones of the
+// WithRunContext and WithShutdownContext options.
 func WithContext(ctx context.Context) Option {
+	return WithInitContext(ctx)
+}
+
+// WithBaseContext provides the context a prepackaged context of an app is derived from in place of
+// the background one, i.e. the root of all the contexts of the app, so that its values are visible
+// throughout and its cancellation is the one of the app. Unlike the WithInitContext option, the
+// context isn't limited to the initialization. It's the sanctioned way to replace the prepackaged
+// context, which no constructor may provide. A scope derives its context from the one of its parent
+// regardless.
+func WithBaseContext(ctx context.Context) Option {
 	return func(options *options) {
-		options.ctx = ctx
+		options.baseCtx = ctx
+	}
+}
+
+// WithDetachedContext makes the prepackaged context of an app cancelled by nothing but shutting the
+// app down: neither an interrupt nor any of the signals (see the WithSignals option) cancel it, and
+// neither does the base context (see the WithBaseContext option), whose values are still visible.
+// It's meant for hosts managing the termination themselves, e.g. tests, GUI apps or other
+// frameworks, that only ever call the Shutdown method explicitly. A scope derives its context from
+// the one of its parent regardless.
+func WithDetachedContext() Option {
+	return func(options *options) {
+		options.detached = true
+	}
+}
+
+// WithPhaseContexts provides the contexts of all the phases of an app at once: the one of the
+// initialization (see the WithInitContext option), and the defaults of the ones of the runs and
+// the shutdown (see the WithRunContext and WithShutdownContext options), which the options
+// provided to the Run and Shutdown methods take precedence over. A nil context is ignored.
+func WithPhaseContexts(initCtx, runCtx, shutdownCtx context.Context) Option {
+	return func(options *options) {
+		if initCtx != nil {
+			options.initCtx = initCtx
+		}
+		if runCtx != nil {
+			options.runCtx = runCtx
+		}
+		if shutdownCtx != nil {
+			options.shutdownCtx = shutdownCtx
+		}
+	}
+}
+
+// WithIntrospection keeps the metadata describing how components were constructed—their
+// constructors and dependencies—available after an app has been initialized, for the App's
+// Dependencies method to report. Otherwise, it's released once the app is initialized.
+func WithIntrospection() Option {
+	return func(options *options) {
+		options.introspection = true
+	}
+}
+
+// WithRejectNil makes a constructor returning a nil component—a nil pointer, interface, map,
+// channel or function—fail the initialization with an error naming the constructor rather than
+// injecting the nil into the components depending on it.
+func WithRejectNil() Option {
+	return func(options *options) {
+		options.rejectNil = true
+	}
+}
+
+// WithSlowInitWarning provides a handler invoked with the name of each initializer—a constructor or
+// an init—taking longer than the threshold to be invoked, and the time it took, e.g. to log slow
+// constructors without resorting to full-fledged metrics or tracing. A nil handler causes an error.
+func WithSlowInitWarning(threshold time.Duration, handler func(string, time.Duration)) Option {
+	site := callSite()
+
+	return func(options *options) {
+		if handler == nil {
+			options.errs = append(
+				options.errs,
+				fmt.Errorf("slow init warning at %s has a nil handler", site),
+			)
+
+			return
+		}
+
+		options.slowInit = &slowInitPolicy{
+			threshold: threshold,
+			handler:   handler,
+		}
+	}
+}
+
+// WithInitTimeout makes each initializer taking a context.Context receive a context derived from
+// the shared one, bounded by the timeout and annotated with the identity of the initializer (see
+// the IdentityFrom function), e.g. for long-running constructors to cooperatively give up once the
+// deadline passes. The context is cancelled once the initializer returns.
+func WithInitTimeout(timeout time.Duration) Option {
+	return func(options *options) {
+		options.initTimeout = timeout
+	}
+}
+
+// WithIdentityContexts makes each initializer taking a context.Context receive a context derived
+// from the shared one and annotated with the identity of the initializer, so that loggers and
+// tracers used inside the initializer can tell which one it is (see the IdentityFrom function).
+func WithIdentityContexts() Option {
+	return func(options *options) {
+		options.identityContexts = true
+	}
+}
+
+// WithVariadicInjection makes initializers taking a variadic argument receive all the components
+// assignable to the type of its elements, in the order the components were provided in, e.g. a
+// constructor of the func(...Plugin) *Registry signature receives every component implementing the
+// Plugin interface. Otherwise, a variadic argument is ignored.
+func WithVariadicInjection() Option {
+	return func(options *options) {
+		options.variadicInjection = true
 	}
 }
 
@@ -82,7 +236,36 @@ func WithOptions(funcOptions ...func(*options)) Option {
 // even if a replacement is provided.
 func WithRunContext(ctx context.Context) RunOption {
 	return func(options *options) {
-		options.ctx = ctx
+		options.runCtx = ctx
+	}
+}
+
+// WithBeforeRun provides a hook invoked with the context provided to the runners before any of
+// them is started, e.g. to announce the app to service discovery. Hooks are invoked in the order
+// they were provided in; an error returned by any fails the run with no runner started.
+func WithBeforeRun(hook func(context.Context, App) error) RunOption {
+	return func(options *options) {
+		options.beforeRun = append(options.beforeRun, hook)
+	}
+}
+
+// WithAfterRun provides a hook invoked once all the runners exit with the error the run results in,
+// e.g. to deregister the app from service discovery. The context provided to the runners is
+// cancelled by then, whereas the one passed to the hook isn't. Hooks are invoked in the reverse
+// order they were provided in, including when a hook provided via the WithBeforeRun option fails.
+func WithAfterRun(hook func(context.Context, App, error)) RunOption {
+	return func(options *options) {
+		options.afterRun = append(options.afterRun, hook)
+	}
+}
+
+// WithRunnerExit provides a callback invoked with the report of each runner once it exits for good,
+// i.e. it won't be restarted anymore (see the WithRestarts option), e.g. to alert on a consumer
+// that has died while the rest of the app keeps running. Callbacks are invoked in the order they
+// were provided in, from the goroutine of the runner, before the run finishes.
+func WithRunnerExit(callback func(RunnerReport)) RunOption {
+	return func(options *options) {
+		options.runnerExit = append(options.runnerExit, callback)
 	}
 }
 
@@ -91,14 +274,152 @@ func WithRunContext(ctx context.Context) RunOption {
 // parent one. It doesn't cease to be taken into account though when the option is provided.
 func WithShutdownContext(ctx context.Context) ShutdownOption {
 	return func(options *options) {
-		options.ctx = ctx
+		options.shutdownCtx = ctx
+	}
+}
+
+// WithRunExitTimeout provides a replacement to the default timeout of 10 seconds bounding the wait
+// for runners to exit when an app is shut down while running. The drainers (see the Drainer
+// interface) are bounded by the timeout as well.
+func WithRunExitTimeout(timeout time.Duration) ShutdownOption {
+	return func(options *options) {
+		options.runExitTimeout = timeout
+	}
+}
+
+// WithShutdownerTimeout bounds each shutdowner's invocation by an individual timeout, so a
+// shutdowner exceeding it doesn't hog the entire shutdown: the context passed to the shutdowner is
+// cancelled, the event is reported (see the WithShutdownErrorHandler option), and the remaining
+// shutdowners are invoked without waiting for the laggard to return.
+func WithShutdownerTimeout(timeout time.Duration) ShutdownOption {
+	return func(options *options) {
+		options.shutdownerTimeout = timeout
+	}
+}
+
+// WithRunValues makes the values of the context provided to the runners, e.g. a deployment ID or
+// trace attributes, available via the context passed to the shutdowners, so that shutdown logs and
+// traces correlate with the run that preceded them. Values of the shutdown context take precedence.
+// The option has no effect if the app hasn't been run.
+func WithRunValues() ShutdownOption {
+	return func(options *options) {
+		options.runValues = true
 	}
 }
 
+// WithShutdownErrorHandler provides a handler of errors reported during a shutdown, e.g. wrapping
+// ErrShutdownTimeout. Otherwise, they're logged with the standard logger.
+func WithShutdownErrorHandler(handler func(context.Context, error)) ShutdownOption {
+	return func(options *options) {
+		options.handler = handler
+	}
+}
+
+const defaultRunExitTimeout = 10 * time.Second
+
+// slowInitPolicy controls the way slow initializers are reported.
+type slowInitPolicy struct {
+	threshold time.Duration
+	handler   func(string, time.Duration)
+}
+
 type options struct {
-	initializers []interface{}
-	components   []interface{}
-	signals      []os.Signal
-	ctx          context.Context
-	handler      func(context.Context, error)
+	errs              []error
+	initializers      []interface{}
+	components        []interface{}
+	embedded          []App
+	signals           []os.Signal
+	baseCtx           context.Context
+	detached          bool
+	initCtx           context.Context
+	runCtx            context.Context
+	shutdownCtx       context.Context
+	handler           func(context.Context, error)
+	introspection     bool
+	rejectNil         bool
+	identityContexts  bool
+	slowInit          *slowInitPolicy
+	exporters         []Exporter
+	scopeTracing      bool
+	crashReporter     func(context.Context, CrashInfo)
+	retryObserver     func(RetryStatus)
+	budgets           map[reflect.Type]Budget
+	budgetPolicy      *budgetPolicy
+	snapshot          map[reflect.Type]reflect.Value
+	values            map[string]interface{}
+	instances         []instanceSet
+	resolvers         []DependencyResolver
+	observers         []interface{}
+	metadata          map[string]string
+	toggles           *DebugToggles
+	verbose           bool
+	deterministic     bool
+	runMiddleware     []func(FuncRunner) FuncRunner
+	stopMiddleware    []func(FuncShutdowner) FuncShutdowner
+	initTimeout       time.Duration
+	initWorkers       int
+	variadicInjection bool
+	runExitTimeout    time.Duration
+	shutdownerTimeout time.Duration
+	runValues         bool
+	runnerShutdown    RunnerShutdownPolicy
+	shutdownDefaults  []ShutdownOption
+	restartPolicy     *restartPolicy
+	stagger           *staggerPolicy
+	smokeRun          time.Duration
+	beforeRun         []func(context.Context, App) error
+	afterRun          []func(context.Context, App, error)
+	runnerExit        []func(RunnerReport)
+	errorBuffer       int
+	overflow          OverflowPolicy
+	exitCodes         []exitCodeMapping
+	probeTarget       string
+	probeTimeout      time.Duration
+}
+
+// callSite reports the location the caller of the function calling it was called from.
+func callSite() string {
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown location"
+	}
+
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+func validateInitializer(initializer interface{}) error {
+	if _, ok := initializer.(phaseMarker); ok {
+		return nil
+	}
+
+	initializer = annotationOf(initializer).initializer
+	if initializer == nil {
+		return errors.New("is nil, expected a function")
+	}
+
+	value := reflect.ValueOf(initializer)
+	if value.Kind() != reflect.Func {
+		return fmt.Errorf("is a %s value, expected a function", value.Type())
+	}
+	if value.IsNil() {
+		return fmt.Errorf("is a nil %s, expected a non-nil function", value.Type())
+	}
+
+	return nil
+}
+
+// phaseDefaults reports the options the Run and Shutdown methods of an app are to apply before
+// the ones provided to them.
+func (o options) phaseDefaults() ([]RunOption, []ShutdownOption) {
+	var runDefaults []RunOption
+	if o.runCtx != nil {
+		runDefaults = append(runDefaults, WithRunContext(o.runCtx))
+	}
+
+	shutdownDefaults := o.shutdownDefaults[:len(o.shutdownDefaults):len(o.shutdownDefaults)]
+	if o.shutdownCtx != nil {
+		shutdownDefaults = append(shutdownDefaults, WithShutdownContext(o.shutdownCtx))
+	}
+
+	return runDefaults, shutdownDefaults
 }
@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// phaseMarker marks the beginning of a phase among initializers.
+type phaseMarker struct {
+	name string
+}
+
+// Phase marks a barrier among initializers provided via the With option: the initializers
+// following the marker belong to the phase named, and all the initializers of a phase, inits
+// included, are invoked before any of the next one, regardless of their dependencies. This is
+// useful when side effects, e.g. global registrations, impose an order the types can't express.
+// Phases are ordered by their first marker; a marker of a phase seen before resumes it, so modules
+// may contribute to a phase. Initializers preceding any marker and components provided via
+// WithComponents belong to an unnamed phase preceding the rest. An initializer mayn't depend on a
+// component of a later phase.
+func Phase(name string) interface{} {
+	return phaseMarker{name: name}
+}
+
+// checkPhases ensures no constructor depends on a component of a later phase.
+func (p *Plan) checkPhases(nodes map[reflect.Type]*node) error {
+	if len(p.phaseNames) < 2 {
+		return nil
+	}
+
+	for _, node := range append(p.nodesOf(nodes), p.inits...) {
+		for _, dependencyType := range node.dependencies {
+			dependency := nodes[dependencyType]
+			if dependency == nil || dependency.phase <= node.phase {
+				continue
+			}
+
+			return fmt.Errorf(
+				"initializer '%s' of phase '%s' depends on '%s' of the later phase '%s'",
+				node.name(),
+				p.phaseNames[node.phase],
+				dependencyType,
+				p.phaseNames[dependency.phase],
+			)
+		}
+	}
+
+	return nil
+}
+
+// splitPhase splits the nodes, ordered by their phases, into the ones of the phase, and of the
+// preceding ones, and the rest.
+func splitPhase(nodes []*node, phase int) ([]*node, []*node) {
+	end := 0
+	for end < len(nodes) && nodes[end].phase <= phase {
+		end++
+	}
+
+	return nodes[:end], nodes[end:]
+}
@@ -0,0 +1,144 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestPhase(t *testing.T) {
+
+	t.Run("barrier", func(t *testing.T) {
+
+		var order []string
+
+		app, err := chariot.New(chariot.With(
+			chariot.Phase("domain"),
+			func() *C {
+
+				order = append(order, "domain")
+
+				return new(C)
+			},
+			chariot.Phase("infra"),
+			func() *D {
+
+				order = append(order, "infra")
+
+				return new(D)
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if !reflect.DeepEqual(order, []string{"domain", "infra"}) {
+			t.Fatal(order)
+		}
+	})
+
+	t.Run("resume", func(t *testing.T) {
+
+		var order []string
+
+		app, err := chariot.New(
+			chariot.With(chariot.Phase("infra"), func() *C {
+
+				order = append(order, "infra")
+
+				return new(C)
+			}),
+			chariot.With(chariot.Phase("domain"), func() *D {
+
+				order = append(order, "domain")
+
+				return new(D)
+			}),
+			chariot.With(chariot.Phase("infra"), func() *E {
+
+				order = append(order, "infra")
+
+				return new(E)
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if !reflect.DeepEqual(order, []string{"infra", "infra", "domain"}) {
+			t.Fatal(order)
+		}
+	})
+
+	t.Run("inits", func(t *testing.T) {
+
+		var order []string
+
+		app, err := chariot.New(chariot.With(
+			chariot.Phase("infra"),
+			func() {
+
+				order = append(order, "infra")
+			},
+			chariot.Phase("domain"),
+			func() *D {
+
+				order = append(order, "domain")
+
+				return new(D)
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if !reflect.DeepEqual(order, []string{"infra", "domain"}) {
+			t.Fatal(order)
+		}
+	})
+
+	t.Run("later-dependency", func(t *testing.T) {
+
+		_, err := chariot.New(chariot.With(
+			chariot.Phase("infra"),
+			func(*D) *C {
+
+				return new(C)
+			},
+			chariot.Phase("domain"),
+			func() *D {
+
+				return new(D)
+			},
+		))
+		if err == nil {
+			t.FailNow()
+		}
+	})
+}
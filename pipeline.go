@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Stage is a stage of a pipeline (see the NewPipeline function). A stage receives the items of the
+// preceding stage from in and sends the items of its own to out until in is closed, after which it
+// returns. The source stage, i.e. the first one, is provided with a nil in and returns once it has
+// produced every item; the sink, i.e. the last one, is provided with a nil out. Stages are to send
+// items selecting on the context, as the context is cancelled once any stage fails.
+type Stage interface {
+	Process(ctx context.Context, in <-chan interface{}, out chan<- interface{}) error
+}
+
+// StageFunc is a function conforming to the Stage interface.
+type StageFunc func(ctx context.Context, in <-chan interface{}, out chan<- interface{}) error
+
+// Pipeline is a runner of stages connected by channels, e.g. for ETL binaries: the run completes
+// once the source stage finishes and the downstream stages drain the items, whereupon an app made
+// of the pipeline alone finishes its run too.
+type Pipeline struct {
+	buffer int
+	stages []Stage
+}
+
+// NewPipeline makes a pipeline of the stages, in the order the items flow through them, connected
+// by channels buffering up to buffer items.
+func NewPipeline(buffer int, stages ...Stage) *Pipeline {
+	return &Pipeline{
+		buffer: buffer,
+		stages: stages,
+	}
+}
+
+// Process delegates the processing to the receiver.
+func (s StageFunc) Process(
+	ctx context.Context,
+	in <-chan interface{},
+	out chan<- interface{},
+) error {
+	return s(ctx, in, out)
+}
+
+// Run runs the stages concurrently and waits till all of them return. The output of a stage is
+// closed once it returns, so that the next one drains it and returns in turn. The first stage to
+// fail aborts the pipeline: the context of the stages is cancelled, and the items left in the
+// channels are discarded. Errors of the stages are aggregated, the one that aborted the pipeline at
+// the head of the list, each prefixed with the index and the name of the stage.
+func (p *Pipeline) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	channels := make([]chan interface{}, len(p.stages)+1)
+	for i := 1; i < len(p.stages); i++ {
+		channels[i] = make(chan interface{}, p.buffer)
+	}
+
+	var (
+		mu        sync.Mutex
+		stageErrs []error
+		finished  sync.WaitGroup
+	)
+	finished.Add(len(p.stages))
+	for i, stage := range p.stages {
+		go func(i int, stage Stage) {
+			defer finished.Done()
+
+			in, out := channels[i], channels[i+1]
+			err := stage.Process(ctx, in, out)
+			if out != nil {
+				close(out)
+			}
+			if err != nil {
+				cancel()
+
+				mu.Lock()
+				stageErrs = append(stageErrs, fmt.Errorf("stage #%d '%s': %w", i, nameOf(stage), err))
+				mu.Unlock()
+			}
+
+			// The items left are discarded, so that the preceding stage isn't blocked sending.
+			if in != nil {
+				for range in {
+				}
+			}
+		}(i, stage)
+	}
+	finished.Wait()
+
+	if len(stageErrs) == 0 {
+		return nil
+	}
+
+	return errors.Join(stageErrs...)
+}
@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestPipeline(t *testing.T) {
+
+	source := func(items int) chariot.StageFunc {
+
+		return func(ctx context.Context, _ <-chan interface{}, out chan<- interface{}) error {
+
+			for i := 1; items < 0 || i <= items; i++ {
+				select {
+				case out <- i:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			return nil
+		}
+	}
+
+	t.Run("drain", func(t *testing.T) {
+
+		double := chariot.StageFunc(func(
+			ctx context.Context,
+			in <-chan interface{},
+			out chan<- interface{},
+		) error {
+
+			for item := range in {
+				select {
+				case out <- item.(int) * 2:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			return nil
+		})
+
+		var sum int
+		sink := chariot.StageFunc(func(
+			_ context.Context,
+			in <-chan interface{},
+			out chan<- interface{},
+		) error {
+
+			if out != nil {
+				t.Error("the sink is provided with an output")
+			}
+			for item := range in {
+				sum += item.(int)
+			}
+
+			return nil
+		})
+
+		app, err := chariot.New(chariot.WithComponents(chariot.NewPipeline(1, source(100), double, sink)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if sum != 10100 {
+			t.Fatal(sum)
+		}
+	})
+
+	t.Run("abort", func(t *testing.T) {
+
+		testErr := errors.New("test")
+
+		fail := chariot.StageFunc(func(
+			_ context.Context,
+			in <-chan interface{},
+			_ chan<- interface{},
+		) error {
+
+			for item := range in {
+				if item.(int) == 3 {
+					return testErr
+				}
+			}
+
+			return nil
+		})
+
+		err := chariot.NewPipeline(0, source(-1), fail).Run(context.Background())
+		if !errors.Is(err, testErr) || !errors.Is(err, context.Canceled) {
+			t.Fatal(err)
+		}
+
+		unwrapped, ok := err.(Unwrapper)
+		if !ok || len(unwrapped.Unwrap()) != 2 || !errors.Is(unwrapped.Unwrap()[0], testErr) {
+			t.Fatal(err)
+		}
+	})
+}
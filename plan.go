@@ -0,0 +1,772 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync/atomic"
+)
+
+// Plan is a resolution of a set of initializers computed once and reusable afterwards. Building
+// and validating the graph of components—catching duplicating components, missing dependencies
+// and cycles—is done when a plan is made, so building an app out of a plan only comes down to
+// invoking initializers in a precomputed order. Workloads building identical apps repeatedly, e.g.
+// serverless handlers and tests, don't pay the price of the resolution each time thus.
+type Plan struct {
+	parent       App
+	funcOptions  []Option
+	options      options
+	constructors []*node
+	lazy         []*node
+	inits        []*node
+	scoped       []interface{}
+	scopedTypes  map[reflect.Type]bool
+	phaseNames   []string
+	factories    []*node
+	predecessors predecessors
+}
+
+// predecessors maps inits to the ones they're to be invoked after (see the After function).
+type predecessors map[*node][]interface{}
+
+// node is an initializer along with its analysed signature and the dependencies it's to be
+// provided with.
+type node struct {
+	signature    *signature
+	initializer  reflect.Value
+	dependencies []reflect.Type
+	lazy         bool
+	startAfter   []reflect.Type
+	retry        *retryPolicy
+	phase        int
+	factory      bool
+	after        []*node
+	description  string
+	snapshotSafe bool
+	onShutdown   reflect.Value
+	reExported   bool
+	fromValue    bool
+}
+
+// name reports the name of the initializer of the node along with its description, if any.
+func (n *node) name() string {
+	if n.description == "" {
+		return funcName(n.initializer)
+	}
+
+	return fmt.Sprintf("%s (%s)", funcName(n.initializer), n.description)
+}
+
+var ctxType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// NewPlan makes a plan out of the options following the rules the New function describes. The
+// initializers aren't invoked in the process.
+func NewPlan(funcOptions ...Option) (*Plan, error) {
+	return newPlan(App{}, funcOptions)
+}
+
+// newPlan makes a plan of an app that inherits the components of the parent, if it's valid.
+func newPlan(parent App, funcOptions []Option) (*Plan, error) {
+	var options options
+	for _, option := range funcOptions {
+		option(&options)
+	}
+	if len(options.errs) != 0 {
+		return nil, errors.Join(options.errs...)
+	}
+
+	plan := Plan{
+		parent:       parent,
+		funcOptions:  funcOptions,
+		options:      options,
+		constructors: make([]*node, 0, len(options.initializers)+len(options.components)),
+	}
+
+	initializers := options.initializers
+	if parent.Valid() {
+		initializers = append(parent.scoped[:len(parent.scoped):len(parent.scoped)], initializers...)
+	}
+	initializers = plan.mergeComponentsInitializers(options.components, initializers)
+	nodes, types, err := plan.collectNodes(initializers)
+	if err == nil && len(options.resolvers) != 0 {
+		nodes, types, err = plan.resolveMissing(initializers, nodes, types)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.checkScopedDependents(nodes); err != nil {
+		return nil, err
+	}
+	if options.variadicInjection {
+		plan.resolveVariadics(nodes, types)
+	}
+	if err := plan.checkPhases(nodes); err != nil {
+		return nil, err
+	}
+	if err := plan.orderConstructors(nodes, types); err != nil {
+		return nil, err
+	}
+	if err := plan.orderInits(); err != nil {
+		return nil, err
+	}
+	if err := plan.validateStartAfter(nodes); err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// Build instantiates a new app out of the plan. The context replaces the one provided via the
+// WithInitContext option, if any, for the duration of the method. A nil context leaves the latter
+// in effect.
+func (p *Plan) Build(ctx context.Context) (App, error) {
+	if ctx == nil {
+		ctx = p.options.initCtx
+	}
+
+	return p.build(ctx)
+}
+
+func (p *Plan) build(ctx context.Context) (_ App, err error) {
+	runDefs, shutdownDefs := p.options.phaseDefaults()
+	metadata := mergeMetadata(p.parent, p.options.metadata)
+	app := App{
+		state: &state{
+			parent:       p.parent,
+			funcOptions:  p.funcOptions,
+			components:   make(map[reflect.Type]*component, len(p.constructors)+len(p.lazy)+8),
+			rejectNil:    p.options.rejectNil,
+			identityCtxs: p.options.identityContexts,
+			slowInit:     p.options.slowInit,
+			exporters:    p.options.exporters,
+			observers:    newObserverSet(p.options.observers, metadata),
+			metadata:     metadata,
+			tracer:       p.tracer(),
+			crashes:      newCrashReporter(p.options.crashReporter),
+			retries:      &retryTracker{observer: p.options.retryObserver},
+			snapshot:     p.options.snapshot,
+			initTimeout:  p.options.initTimeout,
+			initWorkers:  p.options.initWorkers,
+			closed:       make(chan struct{}),
+			ready:        make(chan struct{}),
+			draining:     make(chan struct{}),
+			scoped:       p.scoped,
+			runDefs:      runDefs,
+			shutdownDefs: shutdownDefs,
+		},
+	}
+
+	app.initializeCtx(p.options.baseCtx, p.options.signals, p.options.detached)
+	if p.parent.Valid() {
+		atomic.AddInt64(&p.parent.scopes, 1)
+	}
+	app.setBuildInfoComponent()
+	app.setSignalsComponent()
+	app.setSpawnerComponent()
+	app.setRunContextComponent()
+	app.setStopTokenComponent()
+	app.setDebugTogglesComponent(p.options)
+	app.setValuesComponent(p.options.values)
+	cancel := app.setCtxComponent(ctx)
+	defer cancel()
+	defer app.resetCtxComponent()
+
+	defer func() {
+		if err == nil {
+			return
+		}
+		app.crashes.fail(app.ctx, "init", err)
+		app.mu.Lock()
+		app.setState(AppFailed)
+		app.mu.Unlock()
+
+		var ctx context.Context
+		app.Retrieve(&ctx)
+		app.Shutdown(WithShutdownContext(ctx))
+	}()
+
+	app.registerLazyConstructors(p.factories)
+	constructors, inits := p.constructors, p.inits
+	for phase := 0; phase == 0 || len(constructors) != 0 || len(inits) != 0; phase++ {
+		var phaseConstructors, phaseInits []*node
+		phaseConstructors, constructors = splitPhase(constructors, phase)
+		phaseInits, inits = splitPhase(inits, phase)
+
+		if err := app.invokeConstructors(phaseConstructors); err != nil {
+			return App{}, p.buildError(app, err)
+		}
+		if phase == 0 {
+			app.registerLazyConstructors(p.lazy)
+		}
+		if err := app.invokeInits(phaseInits); err != nil {
+			return App{}, p.buildError(app, err)
+		}
+	}
+	if err := app.instantiate(p.options.instances); err != nil {
+		return App{}, p.buildError(app, err)
+	}
+	if !p.options.introspection {
+		app.releaseConstructionMetadata()
+	}
+	app.embed(p.options.embedded)
+
+	app.mu.Lock()
+	app.setState(AppReady)
+	app.mu.Unlock()
+
+	return app, nil
+}
+
+// buildError describes how far the initialization of the app went before failing with the error.
+func (p *Plan) buildError(app App, err error) *BuildError {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	buildErr := BuildError{Err: err}
+	for _, nodes := range [][]*node{p.constructors, p.lazy} {
+		for _, node := range nodes {
+			for _, componentType := range node.signature.components {
+				component, ok := app.components[componentType]
+				switch {
+				case ok && component.value.IsValid():
+					buildErr.Constructed = append(buildErr.Constructed, componentType)
+				case !node.lazy:
+					buildErr.Pending = append(buildErr.Pending, componentType)
+				}
+			}
+		}
+	}
+
+	return &buildErr
+}
+
+// tracer reports the tracer of resolutions the app is to share with its parent, or a tracer of its
+// own if the app isn't a scope and is to trace its scopes.
+func (p *Plan) tracer() *resolutionTracer {
+	switch {
+	case p.parent.Valid():
+		return p.parent.tracer
+	case p.options.scopeTracing:
+		return new(resolutionTracer)
+	default:
+		return nil
+	}
+}
+
+func (Plan) mergeComponentsInitializers(components, initializers []interface{}) []interface{} {
+	if len(components) != 0 {
+		initializers = append(initializers[:len(initializers):len(initializers)], phaseMarker{})
+	}
+	for _, component := range components {
+		component := component
+		constructor := reflect.MakeFunc(
+			reflect.FuncOf(
+				nil,
+				[]reflect.Type{
+					reflect.TypeOf(component),
+				},
+				false,
+			),
+			func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{
+					reflect.ValueOf(component),
+				}
+			},
+		)
+		annotated := annotate(constructor.Interface(), func(annotation *annotation) {
+			annotation.fromValue = true
+		})
+		initializers = append(initializers, annotated)
+	}
+
+	return initializers
+}
+
+// collectNodes maps component types to the nodes constructing them. The types are also reported
+// in the order they were provided in.
+func (p *Plan) collectNodes(
+	initializers []interface{},
+) (map[reflect.Type]*node, []reflect.Type, error) {
+	nodes := make(map[reflect.Type]*node, len(initializers)+2)
+	nodes[ctxType] = nil
+	nodes[buildInfoType] = nil
+	nodes[signalsType] = nil
+	nodes[spawnerType] = nil
+	nodes[runContextType] = nil
+	nodes[stopTokenType] = nil
+	nodes[debugTogglesType] = nil
+	nodes[valuesType] = nil
+	nodes[componentInfoType] = nil
+	types := append(
+		make([]reflect.Type, 0, len(initializers)+8),
+		ctxType,
+		buildInfoType,
+		signalsType,
+		spawnerType,
+		runContextType,
+		stopTokenType,
+		debugTogglesType,
+		valuesType,
+	)
+	inherited := make(map[reflect.Type]bool)
+	if p.parent.Valid() {
+		for _, componentType := range p.parent.componentTypes() {
+			if _, ok := nodes[componentType]; !ok {
+				nodes[componentType] = nil
+				inherited[componentType] = true
+				types = append(types, componentType)
+			}
+		}
+	}
+	phases := map[string]int{"": 0}
+	p.phaseNames = []string{""}
+	current := 0
+	for _, initializer := range initializers {
+		if marker, ok := initializer.(phaseMarker); ok {
+			index, ok := phases[marker.name]
+			if !ok {
+				index = len(p.phaseNames)
+				phases[marker.name] = index
+				p.phaseNames = append(p.phaseNames, marker.name)
+			}
+			current = index
+
+			continue
+		}
+
+		annotation := annotationOf(initializer)
+		initializer := reflect.ValueOf(annotation.initializer)
+
+		signature, err := signatureOf(initializer.Type(), annotation.errorAt)
+		if err == nil {
+			signature, err = annotation.provided(signature)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("initializer '%s' %w", funcName(initializer), err)
+		}
+
+		if annotation.scoped {
+			if err := p.collectScoped(annotation, signature, nodes); err != nil {
+				return nil, nil, err
+			}
+
+			continue
+		}
+
+		node := node{
+			signature:    signature,
+			initializer:  initializer,
+			dependencies: signature.dependencies,
+			lazy:         annotation.lazy || annotation.factory,
+			startAfter:   annotation.startAfter,
+			retry:        annotation.retry,
+			phase:        current,
+			factory:      annotation.factory,
+			description:  annotation.description,
+			snapshotSafe: annotation.snapshot,
+			reExported:   annotation.reExported,
+			fromValue:    annotation.fromValue,
+		}
+		if annotation.onShutdown != nil {
+			node.onShutdown = reflect.ValueOf(annotation.onShutdown)
+		}
+		if err := p.checkFactory(&node); err != nil {
+			return nil, nil, err
+		}
+
+		if len(node.signature.components) == 0 {
+			if node.lazy {
+				return nil, nil, fmt.Errorf("init '%s' is annotated as lazy", funcName(initializer))
+			}
+			p.inits = append(p.inits, &node)
+			if len(annotation.after) != 0 {
+				if p.predecessors == nil {
+					p.predecessors = make(predecessors)
+				}
+				p.predecessors[&node] = annotation.after
+			}
+
+			continue
+		}
+		if len(annotation.after) != 0 {
+			return nil, nil, fmt.Errorf(
+				"constructor '%s' is annotated with After, which only orders inits",
+				funcName(initializer),
+			)
+		}
+
+		for _, componentType := range node.signature.components {
+			if isPrepackaged(componentType) {
+				return nil, nil, prepackagedError(node.name(), componentType)
+			}
+			if annotation.override && inherited[componentType] {
+				// The type is already listed, so the override only takes the place of the parent.
+				delete(inherited, componentType)
+				nodes[componentType] = &node
+
+				continue
+			}
+			if _, ok := nodes[componentType]; ok || p.scopedTypes[componentType] {
+				return nil, nil, fmt.Errorf("%w '%s'", ErrDuplicateComponent, componentType)
+			}
+			if annotation.override {
+				return nil, nil, fmt.Errorf(
+					"constructor '%s' overrides the component '%s', which isn't inherited",
+					node.name(),
+					componentType,
+				)
+			}
+			if err := p.checkMethodSet(componentType); err != nil {
+				return nil, nil, err
+			}
+
+			nodes[componentType] = &node
+			types = append(types, componentType)
+		}
+		if node.factory {
+			p.factories = append(p.factories, &node)
+		}
+	}
+
+	return nodes, types, nil
+}
+
+// collectScoped sets a scoped constructor aside for the scopes of the app to construct its
+// components.
+func (p *Plan) collectScoped(
+	annotated annotation,
+	signature *signature,
+	nodes map[reflect.Type]*node,
+) error {
+	initializer := reflect.ValueOf(annotated.initializer)
+	if len(signature.components) == 0 {
+		return fmt.Errorf("init '%s' is annotated as scoped", funcName(initializer))
+	}
+
+	if p.scopedTypes == nil {
+		p.scopedTypes = make(map[reflect.Type]bool)
+	}
+	for _, componentType := range signature.components {
+		if isPrepackaged(componentType) {
+			return prepackagedError(funcName(initializer), componentType)
+		}
+		if _, ok := nodes[componentType]; ok || p.scopedTypes[componentType] {
+			return fmt.Errorf("%w '%s'", ErrDuplicateComponent, componentType)
+		}
+		p.scopedTypes[componentType] = true
+	}
+
+	annotated.scoped = false
+	p.scoped = append(p.scoped, &annotated)
+
+	return nil
+}
+
+// prepackagedError reports a constructor providing a prepackaged component, pointing out the way
+// to replace the component if there's one.
+func prepackagedError(constructor string, componentType reflect.Type) error {
+	err := fmt.Errorf(
+		"%w '%s': constructor '%s' provides a prepackaged component",
+		ErrDuplicateComponent,
+		componentType,
+		constructor,
+	)
+	if componentType == ctxType {
+		return fmt.Errorf("%w; replace it via the WithBaseContext option instead", err)
+	}
+
+	return err
+}
+
+// checkScopedDependents ensures no initializer of the app depends on a scoped component.
+func (p *Plan) checkScopedDependents(nodes map[reflect.Type]*node) error {
+	if len(p.scopedTypes) == 0 {
+		return nil
+	}
+
+	for _, node := range append(p.nodesOf(nodes), p.inits...) {
+		for _, dependency := range node.dependencies {
+			if p.scopedTypes[dependency] {
+				return fmt.Errorf(
+					"initializer '%s' of the app depends on the scoped component '%s'",
+					node.name(),
+					dependency,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// nodesOf reports the distinct nodes of the map.
+func (Plan) nodesOf(nodes map[reflect.Type]*node) []*node {
+	seen := make(map[*node]bool, len(nodes))
+	distinct := make([]*node, 0, len(nodes))
+	for _, node := range nodes {
+		if node == nil || seen[node] {
+			continue
+		}
+		seen[node] = true
+		distinct = append(distinct, node)
+	}
+
+	return distinct
+}
+
+var (
+	runnerType     = reflect.TypeOf((*Runner)(nil)).Elem()
+	shutdownerType = reflect.TypeOf((*Shutdowner)(nil)).Elem()
+)
+
+// checkMethodSet catches a component provided as a value while having the Run or the Shutdown
+// method on its pointer receiver, which would otherwise silently deprive it of being run or shut
+// down.
+func (Plan) checkMethodSet(componentType reflect.Type) error {
+	if componentType.Kind() == reflect.Interface || componentType.Kind() == reflect.Ptr {
+		return nil
+	}
+
+	ptrType := reflect.PtrTo(componentType)
+	for _, lifecycle := range [...]struct {
+		iface  reflect.Type
+		method string
+	}{
+		{runnerType, "Run"},
+		{shutdownerType, "Shutdown"},
+	} {
+		if !componentType.Implements(lifecycle.iface) && ptrType.Implements(lifecycle.iface) {
+			return fmt.Errorf(
+				"component '%s' has the %s method on its pointer receiver but is provided as a "+
+					"value; provide '%s' instead",
+				componentType,
+				lifecycle.method,
+				ptrType,
+			)
+		}
+	}
+
+	return nil
+}
+
+// resolveVariadics extends dependencies of initializers taking a variadic argument with all the
+// components assignable to the type of its elements barring the ones the initializers construct
+// themselves.
+func (p *Plan) resolveVariadics(nodes map[reflect.Type]*node, types []reflect.Type) {
+	resolve := func(node *node) {
+		if node.signature.variadic == nil {
+			return
+		}
+
+		dependencies := append([]reflect.Type(nil), node.signature.dependencies...)
+		for _, componentType := range types {
+			if nodes[componentType] != node && componentType.AssignableTo(node.signature.variadic) {
+				dependencies = append(dependencies, componentType)
+			}
+		}
+		node.dependencies = dependencies
+	}
+
+	for _, node := range nodes {
+		if node != nil {
+			resolve(node)
+		}
+	}
+	for _, init := range p.inits {
+		resolve(init)
+	}
+}
+
+// orderConstructors orders constructors so each is preceded by its dependencies. Lazy constructors
+// are ordered separately unless an eager initializer depends on them.
+func (p *Plan) orderConstructors(nodes map[reflect.Type]*node, types []reflect.Type) error {
+	states := make(map[*node]visitState, len(nodes))
+
+	for _, componentType := range types {
+		root := nodes[componentType]
+		if root == nil || root.lazy {
+			continue
+		}
+
+		if err := p.orderConstructor(root, nodes, states, &p.constructors); err != nil {
+			return err
+		}
+	}
+	for _, init := range p.inits {
+		for _, dependencyType := range init.dependencies {
+			dependency, ok := nodes[dependencyType]
+			if !ok {
+				return fmt.Errorf("%w '%s'", ErrMissingDependency, dependencyType)
+			}
+
+			if err := p.orderConstructor(dependency, nodes, states, &p.constructors); err != nil {
+				return err
+			}
+		}
+	}
+	sort.SliceStable(p.constructors, func(i, j int) bool {
+		return p.constructors[i].phase < p.constructors[j].phase
+	})
+	sort.SliceStable(p.inits, func(i, j int) bool {
+		return p.inits[i].phase < p.inits[j].phase
+	})
+	for _, componentType := range types {
+		root := nodes[componentType]
+		if root == nil || !root.lazy {
+			continue
+		}
+
+		if err := p.orderConstructor(root, nodes, states, &p.lazy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type visitState int
+
+const (
+	unvisited visitState = iota
+	visiting
+	visited
+)
+
+func (Plan) orderConstructor(
+	root *node,
+	nodes map[reflect.Type]*node,
+	states map[*node]visitState,
+	ordered *[]*node,
+) error {
+	if root == nil || states[root] != unvisited {
+		return nil
+	}
+
+	type frame struct {
+		node *node
+		next int
+	}
+
+	states[root] = visiting
+	stack := []frame{
+		{
+			node: root,
+		},
+	}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == len(top.node.dependencies) {
+			states[top.node] = visited
+			if !top.node.factory {
+				*ordered = append(*ordered, top.node)
+			}
+			stack = stack[:len(stack)-1]
+
+			continue
+		}
+
+		dependencyType := top.node.dependencies[top.next]
+		top.next++
+
+		dependency, ok := nodes[dependencyType]
+		if !ok {
+			return fmt.Errorf("%w '%s'", ErrMissingDependency, dependencyType)
+		}
+		if dependency == nil {
+			continue
+		}
+
+		switch states[dependency] {
+		case visiting:
+			return fmt.Errorf("dependency %w", ErrCycle)
+		case visited:
+			continue
+		}
+
+		states[dependency] = visiting
+		stack = append(stack, frame{
+			node: dependency,
+		})
+	}
+
+	return nil
+}
+
+// validateStartAfter ensures runners are to start after runners and the constraints don't form a
+// cycle.
+func (Plan) validateStartAfter(nodes map[reflect.Type]*node) error {
+	var (
+		states = make(map[reflect.Type]visitState, len(nodes))
+		visit  func(reflect.Type) error
+	)
+	visit = func(runnerType reflect.Type) error {
+		switch states[runnerType] {
+		case visiting:
+			return fmt.Errorf("start order %w", ErrCycle)
+		case visited:
+			return nil
+		}
+		states[runnerType] = visiting
+
+		for _, prerequisite := range nodes[runnerType].startAfter {
+			if err := visit(prerequisite); err != nil {
+				return err
+			}
+		}
+
+		states[runnerType] = visited
+
+		return nil
+	}
+
+	var runners []reflect.Type
+	for componentType, node := range nodes {
+		if node == nil || len(node.startAfter) == 0 || !componentType.Implements(runnerType) {
+			continue
+		}
+
+		// All the prerequisites are checked before any is visited, as they're visited recursively.
+		for _, prerequisite := range node.startAfter {
+			if nodes[prerequisite] == nil || !prerequisite.Implements(runnerType) {
+				return fmt.Errorf(
+					"runner '%s' is to start after a missing runner '%s'",
+					componentType,
+					prerequisite,
+				)
+			}
+		}
+		runners = append(runners, componentType)
+	}
+
+	for _, componentType := range runners {
+		if err := visit(componentType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
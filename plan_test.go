@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestPlan(t *testing.T) {
+
+	t.Run("reusable", func(t *testing.T) {
+
+		var calls int
+
+		plan, err := chariot.NewPlan(
+			chariot.With(
+				func(*B) *A {
+
+					calls++
+
+					return new(A)
+				},
+				func() *B {
+
+					return new(B)
+				},
+			),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app1, err := plan.Build(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app1.Shutdown()
+
+		app2, err := plan.Build(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app2.Shutdown()
+
+		var a1, a2 *A
+		switch {
+		case calls != 2:
+			t.Fatal(calls)
+		case !app1.Retrieve(&a1):
+			t.FailNow()
+		case !app2.Retrieve(&a2):
+			t.FailNow()
+		case a1 == a2:
+			t.FailNow()
+		}
+	})
+
+	t.Run("validated-upfront", func(t *testing.T) {
+
+		var called bool
+
+		_, err := chariot.NewPlan(
+			chariot.With(
+				func() *A {
+
+					called = true
+
+					return new(A)
+				},
+				func(*C) *B {
+
+					return new(B)
+				},
+			),
+		)
+		switch {
+		case err == nil:
+			t.FailNow()
+		case called:
+			t.FailNow()
+		}
+	})
+
+	t.Run("build-context", func(t *testing.T) {
+
+		key := new(struct{})
+
+		plan, err := chariot.NewPlan(
+			chariot.With(func(ctx context.Context) *A {
+
+				if value := ctx.Value(key); value != key {
+					t.Fatal(value)
+				}
+
+				return new(A)
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app, err := plan.Build(context.WithValue(context.Background(), key, key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+	})
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package plugins extends apps with modules of Go plugins, enabling out-of-tree extensions of a
+// platform built on chariot. A plugin is a main package built with -buildmode=plugin that exports a
+// function named Module:
+//
+//	func Module() chariot.Module
+//
+// Plugins are only supported on the platforms the standard plugin package supports.
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+
+	"github.com/rwyyr/chariot"
+)
+
+// Extension is the suffix of the files Load treats as plugins.
+const Extension = ".so"
+
+// Load opens the plugins in the directory, in lexical order of their file names, and combines the
+// modules they export into one to be passed to an app's initialization. An error is returned if
+// the directory can't be read, a plugin fails to open, or it doesn't export a conformant Module
+// function.
+func Load(dir string) (chariot.Module, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Extension))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	module := chariot.WithOptions()
+	for _, path := range paths {
+		pluginModule, err := open(path)
+		if err != nil {
+			return nil, err
+		}
+		module = chariot.WithOptions(module, pluginModule)
+	}
+
+	return module, nil
+}
+
+func open(path string) (chariot.Module, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("plugin '%s': %w", path, err)
+	}
+
+	symbol, err := p.Lookup("Module")
+	if err != nil {
+		return nil, fmt.Errorf("plugin '%s': %w", path, err)
+	}
+
+	constructor, ok := symbol.(func() chariot.Module)
+	if !ok {
+		return nil, fmt.Errorf("plugin '%s' exports Module of type '%T'", path, symbol)
+	}
+
+	return constructor(), nil
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package plugins_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/plugins"
+)
+
+func TestLoad(t *testing.T) {
+
+	t.Run("empty", func(t *testing.T) {
+
+		module, err := plugins.Load(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app, err := chariot.New(module)
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+	})
+
+	t.Run("missing", func(t *testing.T) {
+
+		if _, err := plugins.Load(filepath.Join(t.TempDir(), "missing")); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "invalid"+plugins.Extension), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := plugins.Load(dir); err == nil {
+			t.FailNow()
+		}
+	})
+}
@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ProbeOption is an option one can provide to the HealthProbeMain function.
+type ProbeOption func(*options)
+
+const (
+	healthcheckFlag     = "healthcheck"
+	defaultProbeTimeout = 5 * time.Second
+)
+
+// HealthProbeMain turns a binary into a health probe of itself. If the binary is invoked with the
+// -healthcheck flag the function queries the health endpoint of a running instance and exits the
+// process with 0 if it's healthy and with 1 otherwise. Without the flag it returns immediately, so
+// it's meant to be called at the very beginning of main. An endpoint is either an HTTP(S) URL, in
+// which case a 2xx status means healthy, or a unix socket in the form of unix:<path>, in which case
+// a successful connection does. An endpoint passed as the flag's value (-healthcheck=<endpoint>)
+// takes precedence over the one provided via the WithProbeTarget option. This enables a Docker
+// HEALTHCHECK instruction in a single-binary image.
+func HealthProbeMain(funcOptions ...ProbeOption) {
+	target, ok := lookupHealthcheckFlag(os.Args[1:])
+	if !ok {
+		return
+	}
+
+	options := options{
+		probeTimeout: defaultProbeTimeout,
+	}
+	for _, option := range funcOptions {
+		option(&options)
+	}
+	if target == "" {
+		target = options.probeTarget
+	}
+
+	if err := probe(target, options.probeTimeout); err != nil {
+		fmt.Fprintf(os.Stderr, "Health check failed: %s\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// WithProbeTarget provides a health endpoint to query when no endpoint is passed with the flag.
+func WithProbeTarget(target string) ProbeOption {
+	return func(options *options) {
+		options.probeTarget = target
+	}
+}
+
+// WithProbeTimeout provides a replacement to the default timeout of 5 seconds a probe is bound by.
+func WithProbeTimeout(timeout time.Duration) ProbeOption {
+	return func(options *options) {
+		options.probeTimeout = timeout
+	}
+}
+
+func lookupHealthcheckFlag(args []string) (string, bool) {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if name == arg {
+			continue
+		}
+
+		if name == healthcheckFlag {
+			return "", true
+		}
+		if value := strings.TrimPrefix(name, healthcheckFlag+"="); value != name {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+func probe(target string, timeout time.Duration) error {
+	if target == "" {
+		return errors.New("no health endpoint provided")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if path := strings.TrimPrefix(target, "unix:"); path != target {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "unix", path)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status '%s'", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+const (
+	probeTargetEnv = "CHARIOT_TEST_PROBE_TARGET"
+	probeArgsEnv   = "CHARIOT_TEST_PROBE_ARGS"
+)
+
+func TestHealthProbeMain(t *testing.T) {
+
+	if target, ok := os.LookupEnv(probeTargetEnv); ok {
+		os.Args = append(os.Args[:1], strings.Fields(os.Getenv(probeArgsEnv))...)
+		chariot.HealthProbeMain(chariot.WithProbeTarget(target))
+
+		os.Exit(2)
+	}
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, _ *http.Request) {
+
+		resp.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, _ *http.Request) {
+
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+
+	probe := func(target string, args ...string) int {
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHealthProbeMain$")
+		cmd.Env = append(
+			os.Environ(),
+			probeTargetEnv+"="+target,
+			probeArgsEnv+"="+strings.Join(args, " "),
+		)
+		if err := cmd.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				return exitErr.ExitCode()
+			}
+			t.Fatal(err)
+		}
+
+		return 0
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+
+		if code := probe(healthy.URL, "-healthcheck"); code != 0 {
+			t.Fatal(code)
+		}
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+
+		if code := probe(unhealthy.URL, "-healthcheck"); code != 1 {
+			t.Fatal(code)
+		}
+	})
+
+	t.Run("flag-target", func(t *testing.T) {
+
+		if code := probe(unhealthy.URL, "-healthcheck="+healthy.URL); code != 0 {
+			t.Fatal(code)
+		}
+	})
+
+	t.Run("no-flag", func(t *testing.T) {
+
+		if code := probe(healthy.URL); code != 2 {
+			t.Fatal(code)
+		}
+	})
+}
@@ -0,0 +1,352 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package prometheus exposes metrics of apps in the Prometheus text format. The package is
+// self-contained, so neither the core nor the users of the package depend on the Prometheus client
+// library: the Registry component collects gauges, counters and summaries, and serves them at
+// /metrics. The framework's own metrics, i.e. the durations of the initializers, the states of the
+// runners and the durations of shutdowns, are published automatically, as the Registry is a
+// chariot.Exporter.
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+const (
+	kindGauge   = "gauge"
+	kindCounter = "counter"
+	kindSummary = "summary"
+)
+
+type (
+	// Registry collects metrics and serves them in the Prometheus text format. The zero value is
+	// ready for use.
+	Registry struct {
+		mu         sync.Mutex
+		families   map[string]*family
+		collectors []func(*Registry)
+	}
+
+	// Labels are the labels of a sample.
+	Labels map[string]string
+
+	family struct {
+		help    string
+		kind    string
+		samples map[string]*sample
+	}
+
+	sample struct {
+		labels string
+		value  float64
+		count  uint64
+	}
+)
+
+// Module registers the registry as a component of an app, mounts it at /metrics of the mux, if it
+// isn't nil, and makes the app export its metrics to the registry (see the chariot.WithExporter
+// option).
+func Module(registry *Registry, mux *http.ServeMux) chariot.Module {
+	if mux != nil {
+		mux.Handle("/metrics", registry)
+	}
+
+	return chariot.WithOptions(
+		chariot.WithComponents(registry),
+		chariot.WithExporter(registry),
+	)
+}
+
+// WithRunMetrics makes an app publish the metrics of the run to the registry: the states and the
+// restarts of the runners, as of the time of the scrape, and the time the runners and the run as a
+// whole took to exit once the app started shutting down. The option may be reused by the runs that
+// follow one another, e.g. of the apps reloaded (see the App.Reload method); the states are the
+// ones of the latest run.
+func WithRunMetrics(registry *Registry) chariot.RunOption {
+	var (
+		mu        sync.Mutex
+		latest    chariot.App
+		current   *runWatch
+		collector sync.Once
+	)
+	watched := func() *runWatch {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return current
+	}
+
+	return chariot.RunOption(chariot.WithOptions(
+		chariot.WithBeforeRun(func(ctx context.Context, app chariot.App) error {
+			watch := runWatch{recorded: make(chan struct{})}
+			mu.Lock()
+			latest, current = app, &watch
+			mu.Unlock()
+
+			collector.Do(func() {
+				registry.collect(func(registry *Registry) {
+					mu.Lock()
+					app := latest
+					mu.Unlock()
+
+					registry.reset("chariot_runner_state")
+					for _, health := range app.Health() {
+						registry.Set("chariot_runner_state", "State of the runner.",
+							Labels{"runner": health.Name, "state": health.State.String()}, 1)
+						registry.Set("chariot_runner_restarts", "Times the runner has been restarted.",
+							Labels{"runner": health.Name}, float64(health.Restarts))
+					}
+				})
+			})
+
+			go func() {
+				<-ctx.Done()
+				watch.cancelled = time.Now()
+				close(watch.recorded)
+			}()
+
+			return nil
+		}),
+		chariot.WithRunnerExit(func(report chariot.RunnerReport) {
+			watch := watched()
+			if !report.Cancelled || watch == nil {
+				return
+			}
+
+			<-watch.recorded
+			exited := report.Started.Add(report.Duration)
+			registry.Set("chariot_runner_shutdown_duration_seconds",
+				"Time the runner took to exit once the app started shutting down.",
+				Labels{"runner": report.Name}, math.Max(exited.Sub(watch.cancelled).Seconds(), 0))
+		}),
+		chariot.WithAfterRun(func(context.Context, chariot.App, error) {
+			// The context provided to the runners is cancelled by now, yet it's not watched if a
+			// preceding before-run hook failed.
+			mu.Lock()
+			watch := current
+			current = nil
+			mu.Unlock()
+			if watch == nil {
+				return
+			}
+
+			<-watch.recorded
+			registry.Observe("chariot_run_shutdown_duration_seconds",
+				"Time the runners took to exit once the app started shutting down.", nil,
+				time.Since(watch.cancelled).Seconds())
+		}),
+	))
+}
+
+// runWatch records the time the app started shutting down at, i.e. the context provided to the
+// runners was cancelled at, in the course of a run. The time is available once recorded is closed.
+type runWatch struct {
+	cancelled time.Time
+	recorded  chan struct{}
+}
+
+// Set sets the value of a gauge.
+func (r *Registry) Set(name, help string, labels Labels, value float64) {
+	r.update(name, help, kindGauge, labels, func(sample *sample) {
+		sample.value = value
+	})
+}
+
+// Add adds the delta to the value of a counter.
+func (r *Registry) Add(name, help string, labels Labels, delta float64) {
+	r.update(name, help, kindCounter, labels, func(sample *sample) {
+		sample.value += delta
+	})
+}
+
+// Observe records an observation of a summary, which is exposed as its sum and count.
+func (r *Registry) Observe(name, help string, labels Labels, value float64) {
+	r.update(name, help, kindSummary, labels, func(sample *sample) {
+		sample.value += value
+		sample.count++
+	})
+}
+
+// Export records a metric of the framework: gauges are set, counters are added to, and timings are
+// observed by summaries.
+func (r *Registry) Export(metric chariot.Metric) {
+	help := "Metric of the chariot framework."
+	switch metric.Kind {
+	case chariot.MetricGauge:
+		r.Set(metric.Name, help, metric.Labels, metric.Value)
+	case chariot.MetricCounter:
+		r.Add(metric.Name, help, metric.Labels, metric.Value)
+	case chariot.MetricTiming:
+		r.Observe(metric.Name, help, metric.Labels, metric.Value)
+	}
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(r.String()))
+}
+
+// String renders the metrics in the Prometheus text format.
+func (r *Registry) String() string {
+	r.mu.Lock()
+	collectors := r.collectors
+	r.mu.Unlock()
+	for _, collector := range collectors {
+		collector(r)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		family := r.families[name]
+		fmt.Fprintf(&builder, "# HELP %s %s\n# TYPE %s %s\n", name, escape(family.help, false), name,
+			family.kind)
+
+		keys := make([]string, 0, len(family.samples))
+		for key := range family.samples {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			sample := family.samples[key]
+			if family.kind != kindSummary {
+				fmt.Fprintf(&builder, "%s%s %s\n", name, sample.labels, format(sample.value))
+
+				continue
+			}
+			fmt.Fprintf(&builder, "%s_sum%s %s\n", name, sample.labels, format(sample.value))
+			fmt.Fprintf(&builder, "%s_count%s %d\n", name, sample.labels, sample.count)
+		}
+	}
+
+	return builder.String()
+}
+
+// update updates the sample of the family, creating either if missing. Updates of a family of a
+// different kind are ignored.
+func (r *Registry) update(name, help, kind string, labels Labels, apply func(*sample)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.families == nil {
+		r.families = make(map[string]*family)
+	}
+	metricFamily, found := r.families[name]
+	if !found {
+		metricFamily = &family{help: help, kind: kind, samples: make(map[string]*sample)}
+		r.families[name] = metricFamily
+	}
+	if metricFamily.kind != kind {
+		return
+	}
+
+	key := render(labels)
+	metricSample, found := metricFamily.samples[key]
+	if !found {
+		metricSample = &sample{labels: key}
+		metricFamily.samples[key] = metricSample
+	}
+	apply(metricSample)
+}
+
+// reset removes the samples of the family, so that those no longer collected aren't exposed.
+func (r *Registry) reset(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if metricFamily, found := r.families[name]; found {
+		metricFamily.samples = make(map[string]*sample)
+	}
+}
+
+// collect registers a collector invoked before the metrics are rendered.
+func (r *Registry) collect(collector func(*Registry)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.collectors = append(r.collectors, collector)
+}
+
+// render renders the labels ordered by name, e.g. {runner="a",state="running"}.
+func render(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escape(labels[name], true)))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// escape escapes backslashes and line feeds, and double quotes in label values.
+func escape(s string, quotes bool) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	if quotes {
+		s = strings.ReplaceAll(s, `"`, `\"`)
+	}
+
+	return s
+}
+
+func format(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
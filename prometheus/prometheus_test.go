@@ -0,0 +1,170 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package prometheus_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/prometheus"
+)
+
+type server struct{}
+
+func newServer() server {
+
+	return server{}
+}
+
+func (server) Run(ctx context.Context) error {
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestModule(t *testing.T) {
+
+	var registry prometheus.Registry
+	mux := http.NewServeMux()
+
+	app, err := chariot.New(prometheus.Module(&registry, mux), chariot.With(newServer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	var retrieved *prometheus.Registry
+	if !app.Retrieve(&retrieved) || retrieved != &registry {
+		t.FailNow()
+	}
+
+	scrape := func() string {
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		body, _ := io.ReadAll(recorder.Body)
+
+		return string(body)
+	}
+
+	ran := make(chan error, 1)
+	go func() {
+
+		ran <- app.Run(prometheus.WithRunMetrics(&registry))
+	}()
+	<-app.Ready()
+
+	metrics := scrape()
+	for _, expected := range []string{
+		"# TYPE chariot_init_duration_seconds summary\n",
+		`_count{initializer="github.com/rwyyr/chariot/prometheus_test.newServer"} 1`,
+		`chariot_runner_transitions_total{runner="prometheus_test.server",state="running"} 1`,
+		`chariot_runner_state{runner="prometheus_test.server",state="running"} 1` + "\n",
+		`chariot_runner_restarts{runner="prometheus_test.server"} 0` + "\n",
+	} {
+		if !strings.Contains(metrics, expected) {
+			t.Fatal(metrics)
+		}
+	}
+
+	app.Shutdown()
+	if err := <-ran; err != nil {
+		t.Fatal(err)
+	}
+
+	metrics = scrape()
+	for _, expected := range []string{
+		`chariot_runner_state{runner="prometheus_test.server",state="exited"} 1` + "\n",
+		`chariot_runner_shutdown_duration_seconds{runner="prometheus_test.server"}`,
+		"chariot_run_shutdown_duration_seconds_count 1\n",
+		`chariot_shutdown_duration_seconds_count{reason="explicit"} 1`,
+	} {
+		if !strings.Contains(metrics, expected) {
+			t.Fatal(metrics)
+		}
+	}
+	running := `chariot_runner_state{runner="prometheus_test.server",state="running"}`
+	if strings.Contains(metrics, running) {
+		t.Fatal(metrics)
+	}
+}
+
+func TestWithRunMetricsReused(t *testing.T) {
+
+	var registry prometheus.Registry
+	option := prometheus.WithRunMetrics(&registry)
+
+	for i := 0; i < 2; i++ {
+		app, err := chariot.New(prometheus.Module(&registry, nil), chariot.With(newServer))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run(option)
+		}()
+		<-app.Ready()
+
+		app.Shutdown()
+		if err := <-ran; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if metrics := registry.String(); !strings.Contains(
+		metrics,
+		"chariot_run_shutdown_duration_seconds_count 2\n",
+	) {
+		t.Fatal(metrics)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+
+	var registry prometheus.Registry
+	registry.Add("requests_total", "Requests served.", prometheus.Labels{"path": `/"a"`}, 1)
+	registry.Add("requests_total", "Requests served.", prometheus.Labels{"path": `/"a"`}, 2)
+	registry.Set("requests_total", "Mismatched kind.", nil, 10)
+	registry.Observe("latency_seconds", "Latency\nof requests.", nil, 0.5)
+	registry.Observe("latency_seconds", "Latency\nof requests.", nil, 1.5)
+
+	expected := `# HELP latency_seconds Latency\nof requests.
+# TYPE latency_seconds summary
+latency_seconds_sum 2
+latency_seconds_count 2
+# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{path="/\"a\""} 3
+`
+	if metrics := registry.String(); metrics != expected {
+		t.Fatal(metrics)
+	}
+}
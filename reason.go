@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+)
+
+// Reason is the reason an app is shut down for, e.g. for shutdowners to choose between aborting
+// fast and flushing everything (see the ShutdownReason function).
+type Reason int
+
+const (
+	// ReasonUnknown means the context isn't one of a shutdown.
+	ReasonUnknown Reason = iota
+	// ReasonExplicit means the app's Shutdown method has been invoked on its own.
+	ReasonExplicit
+	// ReasonSignal means the app has received a signal (see the WithSignals option).
+	ReasonSignal
+	// ReasonInitFailure means the app has failed to initialize and is being rolled back.
+	ReasonInitFailure
+	// ReasonRunFailure means the app's run has returned an error other than a cancelled context.
+	ReasonRunFailure
+)
+
+type reasonKey struct{}
+
+// ShutdownReason reports the reason of the shutdown the context is passed to shutdowners in the
+// course of. The reason of a shutdown of an app nested in another shutdown, e.g. an embedded one
+// (see the WithEmbedded option), is the one of the latter.
+func ShutdownReason(ctx context.Context) Reason {
+	reason, _ := ctx.Value(reasonKey{}).(Reason)
+
+	return reason
+}
+
+// String returns the name of the reason.
+func (r Reason) String() string {
+	switch r {
+	case ReasonUnknown:
+		return "unknown"
+	case ReasonExplicit:
+		return "explicit"
+	case ReasonSignal:
+		return "signal"
+	case ReasonInitFailure:
+		return "init failure"
+	case ReasonRunFailure:
+		return "run failure"
+	default:
+		return fmt.Sprintf("Reason(%d)", int(r))
+	}
+}
+
+// shutdownReason infers the reason the app is being shut down for. It's to be called before the
+// app is closed.
+func (a App) shutdownReason() Reason {
+	a.mu.RLock()
+	signal, failed, ran := a.signal, a.failed, a.runCtx != nil
+	a.mu.RUnlock()
+
+	switch {
+	case signal:
+		return ReasonSignal
+	case failed && ran:
+		return ReasonRunFailure
+	case failed:
+		return ReasonInitFailure
+	default:
+		return ReasonExplicit
+	}
+}
@@ -0,0 +1,152 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestShutdownReason(t *testing.T) {
+
+	newApp := func(reason *chariot.Reason, initializers ...interface{}) (chariot.App, error) {
+
+		return chariot.New(chariot.With(append([]interface{}{func() A {
+
+			var a A
+			a.mocks.Shutdown = func(ctx context.Context) {
+
+				*reason = chariot.ShutdownReason(ctx)
+			}
+
+			return a
+		}}, initializers...)...))
+	}
+
+	t.Run("explicit", func(t *testing.T) {
+
+		var reason chariot.Reason
+		app, err := newApp(&reason)
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+
+		if reason != chariot.ReasonExplicit {
+			t.Fatal(reason)
+		}
+	})
+
+	t.Run("init-failure", func(t *testing.T) {
+
+		var reason chariot.Reason
+		_, err := newApp(&reason, func(A) (*C, error) {
+
+			return nil, errors.New("failure")
+		})
+		if err == nil {
+			t.FailNow()
+		}
+
+		if reason != chariot.ReasonInitFailure {
+			t.Fatal(reason)
+		}
+	})
+
+	t.Run("run-failure", func(t *testing.T) {
+
+		var reason chariot.Reason
+		app, err := newApp(&reason, func() chariot.FuncRunner {
+
+			return func(context.Context) error {
+
+				return errors.New("failure")
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Run(); err == nil {
+			t.FailNow()
+		}
+		app.Shutdown()
+
+		if reason != chariot.ReasonRunFailure {
+			t.Fatal(reason)
+		}
+	})
+
+	t.Run("signal", func(t *testing.T) {
+
+		switch runtime.GOOS {
+		case "windows", "js", "wasip1":
+			t.Skip("interrupts can't be sent on", runtime.GOOS)
+		}
+
+		var reason chariot.Reason
+		app, err := newApp(&reason, func() chariot.FuncRunner {
+
+			return func(ctx context.Context) error {
+
+				<-ctx.Done()
+
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		process, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+
+			<-app.Ready()
+			if err := process.Signal(os.Interrupt); err != nil {
+				t.Error(err)
+			}
+		}()
+		if err := app.Run(); !errors.Is(err, context.Canceled) {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+
+		if reason != chariot.ReasonSignal {
+			t.Fatal(reason)
+		}
+	})
+
+	t.Run("outside-shutdown", func(t *testing.T) {
+
+		if reason := chariot.ShutdownReason(context.Background()); reason != chariot.ReasonUnknown {
+			t.Fatal(reason)
+		}
+	})
+}
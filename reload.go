@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import "fmt"
+
+// Inheritor stands for any conformant component of a reloaded app that takes over from its
+// counterpart of the app being reloaded, e.g. a server adopting the listener of its predecessor so
+// that no connection is refused in the process.
+type Inheritor interface {
+	Inherit(predecessor interface{}) error
+}
+
+// Reload initializes a new app alongside the app out of the options the latter was initialized
+// with followed by the ones given. Once the new app is initialized, each of its
+// Inheritor-conformant components inherits from the component of the same type of the app, if
+// there is one, and the app is shut down then, its runners exiting. The new app is to be run in
+// place of the app afterwards. The app stays intact if the new one fails to initialize or inherit;
+// ErrAppClosed is returned if the app has been shut down. Note that components provided with the
+// WithComponents option are handed to the new app as they are: a Shutdowner-conformant one is shut
+// down along with the app while the new app keeps it, so provide such components via constructors
+// for them to be made anew.
+func (a App) Reload(funcOptions ...Option) (App, error) {
+	if !a.Valid() || a.isClosed() {
+		return App{}, ErrAppClosed
+	}
+
+	a.mu.RLock()
+	retained := a.funcOptions
+	a.mu.RUnlock()
+	funcOptions = append(retained[:len(retained):len(retained)], funcOptions...)
+
+	plan, err := newPlan(a.parent, funcOptions)
+	if err != nil {
+		return App{}, err
+	}
+
+	reloaded, err := plan.build(plan.options.initCtx)
+	if err != nil {
+		return App{}, err
+	}
+
+	if err := reloaded.inherit(a); err != nil {
+		reloaded.Shutdown()
+
+		return App{}, err
+	}
+	a.Shutdown()
+
+	// The options are released, as they may hold on to components of the app.
+	a.mu.Lock()
+	a.funcOptions = nil
+	a.mu.Unlock()
+
+	return reloaded, nil
+}
+
+// inherit makes Inheritor-conformant components inherit from their predecessors.
+func (a App) inherit(predecessor App) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	predecessor.mu.RLock()
+	defer predecessor.mu.RUnlock()
+
+	for componentType, component := range a.components {
+		if !component.value.IsValid() {
+			continue
+		}
+
+		inheritor, ok := component.value.Interface().(Inheritor)
+		if !ok {
+			continue
+		}
+
+		predecessorComponent, ok := predecessor.components[componentType]
+		if !ok || !predecessorComponent.value.IsValid() {
+			continue
+		}
+
+		if err := inheritor.Inherit(predecessorComponent.value.Interface()); err != nil {
+			return fmt.Errorf("component '%s' failed to inherit: %w", componentType, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+type listener struct {
+	inherited *listener
+	fail      bool
+}
+
+func (l *listener) Inherit(predecessor interface{}) error {
+
+	if l.fail {
+		return errors.New("failure")
+	}
+	l.inherited = predecessor.(*listener)
+
+	return nil
+}
+
+func TestAppReload(t *testing.T) {
+
+	t.Run("inherit", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func() *listener {
+
+			return new(listener)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var old *listener
+		if !app.Retrieve(&old) {
+			t.FailNow()
+		}
+
+		reloaded, err := app.Reload()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reloaded.Shutdown()
+
+		var l *listener
+		switch {
+		case !reloaded.Retrieve(&l):
+			t.FailNow()
+		case l == old || l.inherited != old:
+			t.FailNow()
+		case app.Retrieve(&l):
+			t.FailNow()
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		var fail bool
+
+		app, err := chariot.New(chariot.With(func() *listener {
+
+			return &listener{fail: fail}
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		fail = true
+		if _, err := app.Reload(); err == nil {
+			t.FailNow()
+		}
+
+		var l *listener
+		if !app.Retrieve(&l) {
+			t.FailNow()
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+
+		if _, err := app.Reload(); err != chariot.ErrAppClosed {
+			t.Fatal(err)
+		}
+	})
+}
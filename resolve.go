@@ -0,0 +1,139 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DependencyResolver resolves a dependency no initializer provides by returning a constructor
+// providing it, e.g. one consulting a service locator, generating a mock in tests or fetching
+// remote configuration. The constructor may be annotated and have dependencies of its own, which
+// are resolved alike if missing. A resolver that doesn't resolve the dependency reports false.
+type DependencyResolver func(dependencyType reflect.Type) (constructor interface{}, ok bool)
+
+// WithDependencyResolver provides a resolver consulted for each dependency no initializer
+// provides before the ErrMissingDependency error is returned. Resolvers are consulted in the order
+// they were provided in till one resolves the dependency. Constructors of resolvers belong to the
+// first phase (see the Phase function).
+func WithDependencyResolver(resolver DependencyResolver) Option {
+	return func(options *options) {
+		options.resolvers = append(options.resolvers, resolver)
+	}
+}
+
+// resolveMissing consults the resolvers for the missing dependencies of the nodes, and collects
+// the nodes anew along with the constructors the resolvers provide till no dependency is resolved.
+func (p *Plan) resolveMissing(
+	initializers []interface{},
+	nodes map[reflect.Type]*node,
+	types []reflect.Type,
+) (map[reflect.Type]*node, []reflect.Type, error) {
+	consulted := make(map[reflect.Type]bool)
+	for {
+		var (
+			resolved     []reflect.Type
+			constructors []interface{}
+		)
+		for _, dependencyType := range p.missingDependencies(nodes, types) {
+			if consulted[dependencyType] {
+				continue
+			}
+			consulted[dependencyType] = true
+
+			constructor, ok := p.consultResolvers(dependencyType)
+			if !ok {
+				continue
+			}
+			if err := validateInitializer(constructor); err != nil {
+				return nil, nil, fmt.Errorf("constructor resolving '%s' %w", dependencyType, err)
+			}
+			resolved = append(resolved, dependencyType)
+			constructors = append(constructors, constructor)
+		}
+		if len(constructors) == 0 {
+			return nodes, types, nil
+		}
+
+		// Resolved constructors go first so that they belong to the first phase.
+		initializers = append(constructors, initializers...)
+		p.inits, p.predecessors, p.factories = nil, nil, nil
+		p.scoped, p.scopedTypes = nil, nil
+
+		var err error
+		nodes, types, err = p.collectNodes(initializers)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, dependencyType := range resolved {
+			if _, ok := nodes[dependencyType]; !ok {
+				return nil, nil, fmt.Errorf(
+					"constructor '%s' resolving '%s' doesn't provide it",
+					funcName(reflect.ValueOf(annotationOf(constructors[i]).initializer)),
+					dependencyType,
+				)
+			}
+		}
+	}
+}
+
+// missingDependencies reports the dependencies of the nodes and the inits of the plan no node
+// provides, in the order the nodes were provided in.
+func (p *Plan) missingDependencies(
+	nodes map[reflect.Type]*node,
+	types []reflect.Type,
+) []reflect.Type {
+	var missing []reflect.Type
+	seen := make(map[*node]bool, len(nodes))
+	check := func(node *node) {
+		for _, dependencyType := range node.signature.dependencies {
+			if _, ok := nodes[dependencyType]; !ok && !p.scopedTypes[dependencyType] {
+				missing = append(missing, dependencyType)
+			}
+		}
+	}
+
+	for _, componentType := range types {
+		if node := nodes[componentType]; node != nil && !seen[node] {
+			seen[node] = true
+			check(node)
+		}
+	}
+	for _, init := range p.inits {
+		check(init)
+	}
+
+	return missing
+}
+
+// consultResolvers consults the resolvers for a constructor providing the dependency.
+func (p *Plan) consultResolvers(dependencyType reflect.Type) (interface{}, bool) {
+	for _, resolver := range p.options.resolvers {
+		if constructor, ok := resolver(dependencyType); ok {
+			return constructor, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithDependencyResolver(t *testing.T) {
+
+	var (
+		cType = reflect.TypeOf(new(C))
+		dType = reflect.TypeOf(new(D))
+	)
+	newC := func() *C {
+
+		return new(C)
+	}
+	newD := func(*C) *D {
+
+		return new(D)
+	}
+
+	t.Run("resolved", func(t *testing.T) {
+
+		var consulted []reflect.Type
+		app, err := chariot.New(
+			chariot.With(func(*D) {}),
+			chariot.WithDependencyResolver(func(dependencyType reflect.Type) (interface{}, bool) {
+
+				consulted = append(consulted, dependencyType)
+				switch dependencyType {
+				case cType:
+					return newC, true
+				case dType:
+					return newD, true
+				default:
+					return nil, false
+				}
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var c *C
+		if !app.Retrieve(&c) {
+			t.FailNow()
+		}
+		if !reflect.DeepEqual(consulted, []reflect.Type{dType, cType}) {
+			t.Fatal(consulted)
+		}
+	})
+
+	t.Run("unresolved", func(t *testing.T) {
+
+		_, err := chariot.New(
+			chariot.With(func(*C) {}),
+			chariot.WithDependencyResolver(func(reflect.Type) (interface{}, bool) {
+
+				return nil, false
+			}),
+		)
+		if !errors.Is(err, chariot.ErrMissingDependency) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("not-provided", func(t *testing.T) {
+
+		_, err := chariot.New(
+			chariot.With(func(*C) {}),
+			chariot.WithDependencyResolver(func(reflect.Type) (interface{}, bool) {
+
+				return func() *F {
+
+					return new(F)
+				}, true
+			}),
+		)
+		if err == nil || !strings.Contains(err.Error(), "doesn't provide it") {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package resolver resolves the addresses of services for components depending on them, e.g.
+// clients of other services. The default implementation resolves names with DNS, caches the
+// addresses and refreshes them once their TTL elapses, running as a runner of an app.
+package resolver
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+type (
+	// Resolver resolves a service, either a host or a host and a port, e.g. "db:5432", to the
+	// addresses of its instances, the port retained.
+	Resolver interface {
+		Resolve(ctx context.Context, service string) ([]string, error)
+	}
+
+	// DNS is a Runner-conformant resolver caching the addresses of services for the TTL. Once run,
+	// it refreshes the addresses of the services resolved so far every TTL, so that resolutions
+	// don't wait for lookups; the addresses are retained if a refresh fails. A TTL of zero or less
+	// disables both the cache and the refreshes.
+	DNS struct {
+		ttl     time.Duration
+		options options
+
+		mu       sync.Mutex
+		services map[string]*resolution
+	}
+
+	// Option stands for an option of a resolver.
+	Option func(*options)
+
+	options struct {
+		lookup  func(context.Context, string) ([]string, error)
+		handler func(error)
+	}
+
+	resolution struct {
+		addresses []string
+		resolved  time.Time
+	}
+)
+
+// Module provides a DNS resolver with the TTL to an app as the Resolver component.
+func Module(ttl time.Duration, funcOptions ...Option) chariot.Module {
+	return chariot.With(func() Resolver {
+		return NewDNS(ttl, funcOptions...)
+	})
+}
+
+// WithLookup provides a replacement to the lookup of hosts by the default resolver of the net
+// package.
+func WithLookup(lookup func(ctx context.Context, host string) ([]string, error)) Option {
+	return func(options *options) {
+		options.lookup = lookup
+	}
+}
+
+// WithErrorHandler provides a handler of errors of refreshes, which are ignored otherwise.
+func WithErrorHandler(handler func(error)) Option {
+	return func(options *options) {
+		options.handler = handler
+	}
+}
+
+// NewDNS makes a DNS resolver caching addresses for the TTL. Services are looked up on every
+// resolution if the TTL isn't positive.
+func NewDNS(ttl time.Duration, funcOptions ...Option) *DNS {
+	options := options{
+		lookup:  net.DefaultResolver.LookupHost,
+		handler: func(error) {},
+	}
+	for _, option := range funcOptions {
+		option(&options)
+	}
+
+	return &DNS{
+		ttl:      ttl,
+		options:  options,
+		services: make(map[string]*resolution),
+	}
+}
+
+// Resolve resolves the service, looking it up unless its addresses were resolved within the TTL.
+// The addresses resolved before are reported if the lookup fails. The addresses are sorted.
+func (d *DNS) Resolve(ctx context.Context, service string) ([]string, error) {
+	d.mu.Lock()
+	cached, found := d.services[service]
+	d.mu.Unlock()
+	if found && time.Since(cached.resolved) < d.ttl {
+		return append([]string(nil), cached.addresses...), nil
+	}
+
+	addresses, err := d.lookup(ctx, service)
+	if err != nil {
+		if found {
+			return append([]string(nil), cached.addresses...), nil
+		}
+
+		return nil, err
+	}
+
+	return append([]string(nil), addresses...), nil
+}
+
+// Run refreshes the addresses of the services resolved so far every TTL till the context is
+// cancelled. Nothing is refreshed if the TTL isn't positive.
+func (d *DNS) Run(ctx context.Context) error {
+	if d.ttl <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(d.ttl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		d.mu.Lock()
+		services := make([]string, 0, len(d.services))
+		for service := range d.services {
+			services = append(services, service)
+		}
+		d.mu.Unlock()
+
+		for _, service := range services {
+			if _, err := d.lookup(ctx, service); err != nil && ctx.Err() == nil {
+				d.options.handler(err)
+			}
+		}
+	}
+}
+
+// lookup looks the host of the service up and caches the addresses.
+func (d *DNS) lookup(ctx context.Context, service string) ([]string, error) {
+	host, port, err := net.SplitHostPort(service)
+	if err != nil {
+		host, port = service, ""
+	}
+
+	hosts, err := d.options.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(hosts))
+	for _, address := range hosts {
+		if port != "" {
+			address = net.JoinHostPort(address, port)
+		}
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.services[service] = &resolution{
+		addresses: addresses,
+		resolved:  time.Now(),
+	}
+
+	return addresses, nil
+}
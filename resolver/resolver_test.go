@@ -0,0 +1,192 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resolver_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/resolver"
+)
+
+type dns struct {
+	mu      sync.Mutex
+	hosts   []string
+	err     error
+	lookups int
+}
+
+func (d *dns) lookup(_ context.Context, host string) ([]string, error) {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.lookups++
+	if host != "db" {
+		return nil, errors.New("no such host")
+	}
+
+	return d.hosts, d.err
+}
+
+func (d *dns) set(hosts []string, err error) {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.hosts, d.err = hosts, err
+}
+
+func TestDNS(t *testing.T) {
+
+	t.Run("cache", func(t *testing.T) {
+
+		d := dns{hosts: []string{"10.0.0.2", "10.0.0.1"}}
+		r := resolver.NewDNS(time.Hour, resolver.WithLookup(d.lookup))
+
+		for i := 0; i < 2; i++ {
+			addresses, err := r.Resolve(context.Background(), "db:5432")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(addresses, []string{"10.0.0.1:5432", "10.0.0.2:5432"}) {
+				t.Fatal(addresses)
+			}
+		}
+		if d.lookups != 1 {
+			t.Fatal(d.lookups)
+		}
+
+		if _, err := r.Resolve(context.Background(), "cache"); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("no-ttl", func(t *testing.T) {
+
+		d := dns{hosts: []string{"10.0.0.1"}}
+
+		app, err := chariot.New(resolver.Module(0, resolver.WithLookup(d.lookup)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var r resolver.Resolver
+		if !app.Retrieve(&r) {
+			t.FailNow()
+		}
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run()
+		}()
+		<-app.Ready()
+
+		for i := 0; i < 2; i++ {
+			if _, err := r.Resolve(context.Background(), "db"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		d.mu.Lock()
+		lookups := d.lookups
+		d.mu.Unlock()
+		if lookups != 2 {
+			t.Fatal(lookups)
+		}
+
+		app.Shutdown()
+		if err := <-ran; err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+
+		d := dns{hosts: []string{"10.0.0.1"}}
+		reported := make(chan error, 1)
+
+		app, err := chariot.New(resolver.Module(
+			time.Millisecond,
+			resolver.WithLookup(d.lookup),
+			resolver.WithErrorHandler(func(err error) {
+
+				select {
+				case reported <- err:
+				default:
+				}
+			}),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var r resolver.Resolver
+		if !app.Retrieve(&r) {
+			t.FailNow()
+		}
+		if _, err := r.Resolve(context.Background(), "db"); err != nil {
+			t.Fatal(err)
+		}
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run()
+		}()
+		<-app.Ready()
+
+		d.set(nil, errors.New("failure"))
+		<-reported
+		if addresses, err := r.Resolve(context.Background(), "db"); err != nil ||
+			!reflect.DeepEqual(addresses, []string{"10.0.0.1"}) {
+			t.Fatal(addresses, err)
+		}
+
+		d.set([]string{"10.0.0.3"}, nil)
+		for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+			addresses, err := r.Resolve(context.Background(), "db")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reflect.DeepEqual(addresses, []string{"10.0.0.3"}) {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal(addresses)
+			}
+		}
+
+		app.Shutdown()
+		if err := <-ran; err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// retryPolicy controls the way an initializer is retried.
+	retryPolicy struct {
+		attempts int
+		backoff  time.Duration
+	}
+
+	// RetryStatus describes an initializer annotated with Retry that's being retried.
+	RetryStatus struct {
+		// Initializer is the name of the initializer.
+		Initializer string
+		// Description is the description of the initializer, if any (see the Describe function).
+		Description string
+		// Attempt is the number of the attempt to be made next, counting from 1.
+		Attempt int
+		// Attempts is the number of attempts in total.
+		Attempts int
+		// Err is the error of the last attempt.
+		Err error
+	}
+
+	// retryTracker keeps track of the initializers of an app being retried.
+	retryTracker struct {
+		mu       sync.Mutex
+		next     uint64
+		ongoing  map[uint64]RetryStatus
+		observer func(RetryStatus)
+	}
+)
+
+// Retry annotates an initializer so that an error it returns, e.g. because of a DNS hiccup while
+// dialing a broker, makes it retried up to the given number of attempts in total before the error
+// is treated as usual. The delay between attempts starts with the backoff and doubles after each
+// attempt. If the initializer depends on the context.Context component, the waiting is aborted as
+// soon as the context is done. Errors of all the attempts are aggregated in the final error. The
+// result is to be provided in place of the initializer.
+func Retry(initializer interface{}, attempts int, backoff time.Duration) interface{} {
+	return annotate(initializer, func(annotation *annotation) {
+		annotation.retry = &retryPolicy{
+			attempts: attempts,
+			backoff:  backoff,
+		}
+	})
+}
+
+// WithRetryObserver provides an observer invoked with the status of an initializer annotated with
+// Retry each time the initializer is about to be retried, e.g. to report the app as degraded
+// rather than merely unready while it's still initializing.
+func WithRetryObserver(observer func(RetryStatus)) Option {
+	return func(options *options) {
+		options.retryObserver = observer
+	}
+}
+
+// Retrying reports the initializers of the app being retried at the moment, e.g. a lazy
+// constructor of a component being retrieved, ordered by the names of the initializers.
+func (a App) Retrying() []RetryStatus {
+	return a.retries.statuses()
+}
+
+// String describes the status the way health reports do, e.g. "degraded: connecting to broker,
+// attempt 4/10". The initializer is identified by its description, if any, or its name otherwise.
+func (s RetryStatus) String() string {
+	subject := s.Description
+	if subject == "" {
+		subject = s.Initializer
+	}
+
+	return fmt.Sprintf("degraded: %s, attempt %d/%d", subject, s.Attempt, s.Attempts)
+}
+
+// call invokes the initializer separating components from an error among the values it returns and
+// retrying it according to its policy.
+func (n *node) call(ins []reflect.Value, retries *retryTracker) ([]reflect.Value, error) {
+	outs, err := n.signature.split(n.initializer.Call(ins))
+	if err == nil || n.retry == nil {
+		return outs, err
+	}
+
+	id := retries.begin()
+	defer retries.end(id)
+
+	ctx := context.Background()
+	for _, in := range ins {
+		if in.Type() == ctxType {
+			ctx = in.Interface().(context.Context)
+		}
+	}
+
+	var (
+		errs  = []error{fmt.Errorf("attempt #1: %w", err)}
+		delay = n.retry.backoff
+	)
+	for attempt := 2; attempt <= n.retry.attempts; attempt++ {
+		retries.update(id, RetryStatus{
+			Initializer: funcName(n.initializer),
+			Description: n.description,
+			Attempt:     attempt,
+			Attempts:    n.retry.attempts,
+			Err:         err,
+		})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, errors.Join(append(errs, ctx.Err())...)
+		}
+		delay *= 2
+
+		var outs []reflect.Value
+		outs, err = n.signature.split(n.initializer.Call(ins))
+		if err == nil {
+			return outs, nil
+		}
+		errs = append(errs, fmt.Errorf("attempt #%d: %w", attempt, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// begin reports the identifier of a call being retried.
+func (t *retryTracker) begin() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.next++
+
+	return t.next
+}
+
+// update records the status of the call and notifies the observer, if any.
+func (t *retryTracker) update(id uint64, status RetryStatus) {
+	t.mu.Lock()
+	if t.ongoing == nil {
+		t.ongoing = make(map[uint64]RetryStatus)
+	}
+	t.ongoing[id] = status
+	t.mu.Unlock()
+
+	if t.observer != nil {
+		t.observer(status)
+	}
+}
+
+// end forgets the call once it's no longer retried.
+func (t *retryTracker) end(id uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.ongoing, id)
+}
+
+func (t *retryTracker) statuses() []RetryStatus {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	statuses := make([]RetryStatus, 0, len(t.ongoing))
+	for _, status := range t.ongoing {
+		statuses = append(statuses, status)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Initializer < statuses[j].Initializer
+	})
+
+	return statuses
+}
@@ -0,0 +1,166 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestRetry(t *testing.T) {
+
+	t.Run("recovers", func(t *testing.T) {
+
+		var attempts int
+
+		app, err := chariot.New(chariot.With(chariot.Retry(func() (*A, error) {
+
+			attempts++
+			if attempts < 3 {
+				return nil, errors.New("transient")
+			}
+
+			return new(A), nil
+		}, 3, time.Millisecond)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if attempts != 3 {
+			t.Fatal(attempts)
+		}
+	})
+
+	t.Run("gives-up", func(t *testing.T) {
+
+		testErr1, testErr2 := errors.New("test error 1"), errors.New("test error 2")
+
+		var attempts int
+
+		app, err := chariot.New(chariot.With(chariot.Retry(func() error {
+
+			attempts++
+			if attempts == 1 {
+				return testErr1
+			}
+
+			return testErr2
+		}, 2, time.Millisecond)))
+		if err != nil {
+			switch {
+			case attempts != 2:
+				t.Fatal(attempts)
+			case !(errors.Is(err, testErr1) && errors.Is(err, testErr2)):
+				t.Fatal(err)
+			}
+
+			return
+		}
+		defer app.Shutdown()
+
+		t.FailNow()
+	})
+
+	t.Run("status", func(t *testing.T) {
+
+		var statuses []string
+		app, err := chariot.New(
+			chariot.WithRetryObserver(func(status chariot.RetryStatus) {
+
+				statuses = append(statuses, status.String())
+			}),
+			chariot.With(chariot.Describe(chariot.Retry(func() (*A, error) {
+
+				if len(statuses) < 2 {
+					return nil, errors.New("transient")
+				}
+
+				return new(A), nil
+			}, 3, time.Millisecond), "connecting to broker")),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		expected := []string{
+			"degraded: connecting to broker, attempt 2/3",
+			"degraded: connecting to broker, attempt 3/3",
+		}
+		if !reflect.DeepEqual(statuses, expected) {
+			t.Fatal(statuses)
+		}
+	})
+
+	t.Run("retrying", func(t *testing.T) {
+
+		attempted := make(chan struct{}, 1)
+		proceed := make(chan struct{})
+		app, err := chariot.New(chariot.With(chariot.Lazy(chariot.Retry(func() (*B, error) {
+
+			select {
+			case attempted <- struct{}{}:
+				return nil, errors.New("transient")
+			default:
+			}
+			<-proceed
+
+			return new(B), nil
+		}, 2, time.Millisecond))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		retrieved := make(chan struct{})
+		go func() {
+
+			defer close(retrieved)
+
+			var b *B
+			app.Retrieve(&b)
+		}()
+
+		deadline := time.Now().Add(time.Second)
+		for len(app.Retrying()) == 0 {
+			if time.Now().After(deadline) {
+				t.FailNow()
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if status := app.Retrying()[0]; status.Attempt != 2 || status.Err == nil {
+			t.Fatal(status)
+		}
+
+		close(proceed)
+		<-retrieved
+		if len(app.Retrying()) != 0 {
+			t.FailNow()
+		}
+	})
+}
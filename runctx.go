@@ -0,0 +1,65 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"reflect"
+)
+
+// RunContext is a context that stays alive throughout the run of an app, unlike the
+// context.Context component, which constructors are provided with the context of the
+// initialization of (see the WithInitContext option). Constructors starting background helpers,
+// e.g. a cache janitor, depend on it to have the helpers outlive the initialization. An app is
+// prepackaged with the component; it's cancelled once the context provided to the runners is, i.e.
+// the run is ending, or the app is shut down, whichever happens first. Scopes share the one of
+// their app.
+type RunContext interface {
+	context.Context
+}
+
+var runContextType = reflect.TypeOf((*RunContext)(nil)).Elem()
+
+func (a App) setRunContextComponent() {
+	if a.parent.Valid() {
+		a.aliveCtx = a.parent.aliveCtx
+	} else {
+		a.aliveCtx, a.cancelAlive = context.WithCancel(a.ctx)
+	}
+	a.components[runContextType] = &component{
+		value: reflect.ValueOf(a.aliveCtx),
+	}
+}
+
+// bindRunContext cancels the RunContext component once the context provided to the runners is
+// cancelled.
+func (a App) bindRunContext(ctx context.Context) {
+	if a.cancelAlive == nil {
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		a.cancelAlive()
+	}()
+}
@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestRunContext(t *testing.T) {
+
+	t.Run("outlives-init", func(t *testing.T) {
+
+		initCtx, cancelInit := context.WithCancel(context.Background())
+
+		var captured chariot.RunContext
+		app, err := chariot.New(
+			chariot.WithInitContext(initCtx),
+			chariot.With(func(ctx chariot.RunContext) chariot.FuncRunner {
+
+				captured = ctx
+
+				return func(ctx context.Context) error {
+
+					<-ctx.Done()
+
+					return nil
+				}
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+		cancelInit()
+
+		if captured.Err() != nil {
+			t.FailNow()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run(chariot.WithRunContext(ctx))
+		}()
+		time.Sleep(10 * time.Millisecond)
+		if captured.Err() != nil {
+			t.FailNow()
+		}
+
+		cancel()
+		<-ran
+		select {
+		case <-captured.Done():
+		case <-time.After(time.Second):
+			t.FailNow()
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ctx chariot.RunContext
+		if !app.Retrieve(&ctx) {
+			t.FailNow()
+		}
+		app.Shutdown()
+
+		if ctx.Err() == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("scope", func(t *testing.T) {
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		scope, err := app.Scope()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ctx chariot.RunContext
+		if !scope.Retrieve(&ctx) {
+			t.FailNow()
+		}
+		scope.Shutdown()
+
+		if ctx.Err() != nil {
+			t.FailNow()
+		}
+	})
+}
@@ -0,0 +1,625 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// retainedFailures is the number of the latest failures of a runner retained for run reports.
+const retainedFailures = 32
+
+// RunnerState is a state of a runner in the course of an app's run.
+type RunnerState int
+
+const (
+	// RunnerPending means the runner hasn't been started yet.
+	RunnerPending RunnerState = iota
+	// RunnerRunning means the runner is running.
+	RunnerRunning
+	// RunnerRestarting means the runner has returned an error and is waiting to be restarted.
+	RunnerRestarting
+	// RunnerFailed means the runner has returned an error and won't be restarted anymore.
+	RunnerFailed
+	// RunnerExited means the runner has returned without an error.
+	RunnerExited
+)
+
+// RunnerHealth describes the state of a runner.
+type RunnerHealth struct {
+	// Name is the name of the runner (see the Named interface).
+	Name string
+	// State is the state of the runner.
+	State RunnerState
+	// Restarts is the number of times the runner has been restarted.
+	Restarts int
+	// Err is the last error the runner has returned, if any.
+	Err error
+}
+
+// RunnerReport describes the outcome of a runner in the course of an app's run.
+type RunnerReport struct {
+	RunnerHealth
+	// Started is the time the runner was first started at, or the zero time if it wasn't.
+	Started time.Time
+	// Duration is the time the runner spent from the first start till the final exit.
+	Duration time.Duration
+	// Cancelled tells whether the runner exited after its context had been cancelled, e.g. due to
+	// another runner failing or the app shutting down.
+	Cancelled bool
+	// Quarantined tells whether the runner tripped the circuit breaker of a restart policy (see
+	// the WithRestarts option).
+	Quarantined bool
+	// Failures are the errors the runner has returned in the order they were returned in, the ones
+	// it was restarted after included. Only the latest 32 are retained.
+	Failures []RunnerFailure
+}
+
+// RunnerFailure describes an error a runner has returned in the course of an app's run.
+type RunnerFailure struct {
+	// Runner is the name of the runner (see the Named interface).
+	Runner string
+	// At is the time the error was returned at.
+	At time.Time
+	// Err is the error.
+	Err error
+}
+
+// RunReport describes the outcome of an app's run.
+type RunReport struct {
+	// Err is the error the Run method returns.
+	Err error
+	// Trigger is the error that caused the runners' context to be cancelled, if any. An error of a
+	// quarantined runner is never deemed one, as it doesn't cancel the context.
+	Trigger error
+	// Started is the time the run started at.
+	Started time.Time
+	// Duration is the time the run took.
+	Duration time.Duration
+	// Runners are the reports of the runners in the order they were collected in.
+	Runners []RunnerReport
+	// Quarantined are the reports of the runners that tripped the circuit breaker of a restart
+	// policy (see the WithRestarts option) while the rest kept running, in the order they were
+	// collected in.
+	Quarantined []RunnerReport
+	// Metadata are the labels of the app (see the WithMetadata option).
+	Metadata map[string]string
+	// DroppedErrors is the number of errors not retained due to the bound of the error buffer (see
+	// the WithErrorBuffer option).
+	DroppedErrors int
+}
+
+// Timeline reports the failures of all the runners ordered by the time they happened at, so that
+// the course of a run is seen as a whole, e.g. in a postmortem.
+func (r RunReport) Timeline() []RunnerFailure {
+	var timeline []RunnerFailure
+	for _, runner := range r.Runners {
+		timeline = append(timeline, runner.Failures...)
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].At.Before(timeline[j].At)
+	})
+
+	return timeline
+}
+
+// partitionQuarantined separates the errors of quarantined runners from the rest, keeping the
+// order of both.
+func partitionQuarantined(errs []error) (fatal, quarantined []error) {
+	for _, err := range errs {
+		if errors.Is(err, ErrRunnerFailed) {
+			quarantined = append(quarantined, err)
+		} else {
+			fatal = append(fatal, err)
+		}
+	}
+
+	return fatal, quarantined
+}
+
+// restartPolicy controls the way failed runners are restarted.
+type restartPolicy struct {
+	maxFailures int
+	window      time.Duration
+	backoff     time.Duration
+}
+
+// WithRestarts makes runners returning an error restarted after the backoff rather than failing
+// the run. A runner that fails maxFailures times within the window trips a circuit breaker: it
+// transitions to the RunnerFailed state reported by the App's Health method and isn't restarted
+// anymore, while the rest of the runners keep running. Errors of failed runners are returned by
+// the Run method once all the runners finish, following the trigger, if any; the RunReport method
+// lists the runners as quarantined. A non-positive maxFailures and a negative window or backoff
+// cause an error.
+func WithRestarts(maxFailures int, window, backoff time.Duration) RunOption {
+	site := callSite()
+
+	return func(options *options) {
+		switch {
+		case maxFailures <= 0:
+			options.errs = append(options.errs, fmt.Errorf(
+				"restarts at %s allow %d failures, expected a positive number",
+				site,
+				maxFailures,
+			))
+		case window < 0 || backoff < 0:
+			options.errs = append(options.errs, fmt.Errorf(
+				"restarts at %s have a negative window of %s or backoff of %s",
+				site,
+				window,
+				backoff,
+			))
+		default:
+			options.restartPolicy = &restartPolicy{
+				maxFailures: maxFailures,
+				window:      window,
+				backoff:     backoff,
+			}
+		}
+	}
+}
+
+// staggerPolicy controls the way the starts of runners are spread over time.
+type staggerPolicy struct {
+	interval time.Duration
+	jitter   time.Duration
+}
+
+// WithStaggeredStart spreads the starts of runners over time, so that a great number of them, e.g.
+// consumers of a broker, don't hit a dependency all at once. The runners are started in the order
+// they were collected in, each the interval after the previous one, plus a random delay of up to
+// the jitter.
+func WithStaggeredStart(interval, jitter time.Duration) RunOption {
+	return func(options *options) {
+		options.stagger = &staggerPolicy{
+			interval: interval,
+			jitter:   jitter,
+		}
+	}
+}
+
+// WithSmokeRun makes a run a smoke test, e.g. of the real binary in a CI/CD pipeline: the runners
+// run for the duration, and are required to get ready within it (see the Readier interface). The
+// context provided to the runners is cancelled then, and the run results in ErrNotReady, naming
+// the runners that weren't ready, along with errors of the runners, if any.
+func WithSmokeRun(duration time.Duration) RunOption {
+	return func(options *options) {
+		options.smokeRun = duration
+	}
+}
+
+// smokeRun drains the runners once the duration elapses and reports those not ready by then.
+func smokeRun(
+	ctx context.Context,
+	cancel func(),
+	duration time.Duration,
+	runners []*managedRunner,
+	readiness []chan struct{},
+) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return nil
+	}
+	defer cancel()
+
+	var names []string
+	for i, ready := range readiness {
+		select {
+		case <-ready:
+		default:
+			names = append(names, nameOf(runners[i].Runner))
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("smoke run: %w: '%s'", ErrNotReady, strings.Join(names, "', '"))
+}
+
+// delay reports the delay of the start of the runner collected at the index.
+func (p *staggerPolicy) delay(i int) time.Duration {
+	if p == nil {
+		return 0
+	}
+
+	delay := time.Duration(i) * p.interval
+	if p.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(p.jitter)))
+	}
+
+	return delay
+}
+
+// Health reports the states of the app's runners in the order they were collected in.
+func (a App) Health() []RunnerHealth {
+	a.mu.RLock()
+	runners := a.runners
+	a.mu.RUnlock()
+
+	health := make([]RunnerHealth, 0, len(runners))
+	for _, runner := range runners {
+		health = append(health, runner.health())
+	}
+
+	return health
+}
+
+// String returns the name of the state.
+func (s RunnerState) String() string {
+	switch s {
+	case RunnerPending:
+		return "pending"
+	case RunnerRunning:
+		return "running"
+	case RunnerRestarting:
+		return "restarting"
+	case RunnerFailed:
+		return "failed"
+	case RunnerExited:
+		return "exited"
+	default:
+		return fmt.Sprintf("RunnerState(%d)", int(s))
+	}
+}
+
+// managedRunner is a runner along with the metadata an app runs it according to.
+type managedRunner struct {
+	Runner
+	componentType reflect.Type
+	startAfter    []reflect.Type
+	exporters     []Exporter
+	observers     observerSet
+	crashes       *crashReporter
+
+	mu        sync.Mutex
+	state     RunnerState
+	restarts  int
+	err       error
+	failures  []RunnerFailure
+	oldest    int
+	tripped   bool
+	started   time.Time
+	stopped   time.Time
+	cancelled bool
+	drained   bool
+}
+
+// run runs the runner restarting it according to the policy, if any.
+func (r *managedRunner) run(
+	ctx context.Context,
+	policy *restartPolicy,
+	middleware []func(FuncRunner) FuncRunner,
+) error {
+	r.mu.Lock()
+	r.started = time.Now()
+	r.mu.Unlock()
+	defer r.stop(ctx)
+
+	run := wrapRunner(r, middleware)
+	if len(middleware) != 0 {
+		ctx = context.WithValue(ctx, runnerNameKey{}, r.name())
+	}
+
+	var failures []time.Time
+	for {
+		r.setState(RunnerRunning, nil)
+
+		err := r.crashes.guard(ctx, "run", r.name, func() error {
+			return run(ctx)
+		})
+		if err != nil {
+			r.recordFailure(err)
+		}
+		switch {
+		case err == nil:
+			r.setState(RunnerExited, nil)
+
+			return nil
+		case policy == nil || ctx.Err() != nil:
+			r.setState(RunnerFailed, err)
+
+			return err
+		}
+
+		now := time.Now()
+		failures = append(failures, now)
+		for len(failures) > 0 && now.Sub(failures[0]) > policy.window {
+			failures = failures[1:]
+		}
+		if len(failures) >= policy.maxFailures {
+			r.mu.Lock()
+			r.tripped = true
+			r.mu.Unlock()
+			r.setState(RunnerFailed, err)
+
+			return fmt.Errorf("%w: %w", ErrRunnerFailed, err)
+		}
+
+		r.setState(RunnerRestarting, err)
+		timer := time.NewTimer(policy.backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			r.setState(RunnerFailed, err)
+
+			return err
+		}
+
+		r.mu.Lock()
+		r.restarts++
+		r.mu.Unlock()
+	}
+}
+
+// recordFailure adds the error to the failures of the runner, replacing the oldest one once
+// retainedFailures are retained.
+func (r *managedRunner) recordFailure(err error) {
+	failure := RunnerFailure{
+		Runner: r.name(),
+		At:     time.Now(),
+		Err:    err,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.failures) < retainedFailures {
+		r.failures = append(r.failures, failure)
+
+		return
+	}
+	r.failures[r.oldest] = failure
+	r.oldest = (r.oldest + 1) % retainedFailures
+}
+
+// name reports the name of the runner.
+func (r *managedRunner) name() string {
+	return nameOf(r.Runner)
+}
+
+// exited reports whether the runner has been started and has exited for good.
+func (r *managedRunner) exited() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return !r.started.IsZero() && (r.state == RunnerExited || r.state == RunnerFailed)
+}
+
+func (r *managedRunner) setState(state RunnerState, err error) {
+	r.mu.Lock()
+	r.state = state
+	if err != nil {
+		r.err = err
+	}
+	r.mu.Unlock()
+
+	if r.crashes != nil {
+		r.crashes.record("runner '%s' %s", r.name(), state)
+	}
+	if len(r.exporters) != 0 {
+		export(r.exporters, Metric{
+			Name:   "chariot_runner_transitions_total",
+			Kind:   MetricCounter,
+			Labels: map[string]string{"runner": nameOf(r.Runner), "state": state.String()},
+			Value:  1,
+		})
+	}
+	if len(r.observers.runner) != 0 {
+		r.observers.observeRunner(RunnerEvent{
+			Runner: r.name(),
+			State:  state,
+			At:     time.Now(),
+			Err:    err,
+		})
+	}
+}
+
+// stop records the runner's final exit.
+func (r *managedRunner) stop(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stopped = time.Now()
+	r.cancelled = ctx.Err() != nil
+}
+
+func (r *managedRunner) report() RunnerReport {
+	health := r.health()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := RunnerReport{
+		RunnerHealth: health,
+		Started:      r.started,
+		Cancelled:    r.cancelled,
+		Quarantined:  r.tripped,
+	}
+	if len(r.failures) != 0 {
+		report.Failures = make([]RunnerFailure, 0, len(r.failures))
+		report.Failures = append(report.Failures, r.failures[r.oldest:]...)
+		report.Failures = append(report.Failures, r.failures[:r.oldest]...)
+	}
+	if !r.started.IsZero() {
+		report.Duration = r.stopped.Sub(r.started)
+	}
+
+	return report
+}
+
+func (r *managedRunner) health() RunnerHealth {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return RunnerHealth{
+		Name:     nameOf(r.Runner),
+		State:    r.state,
+		Restarts: r.restarts,
+		Err:      r.err,
+	}
+}
+
+// signalReady closes the channel once the runner is ready.
+func (r *managedRunner) signalReady(ctx context.Context, ready chan struct{}) {
+	readier, ok := r.Runner.(Readier)
+	if !ok {
+		close(ready)
+
+		return
+	}
+
+	go func() {
+		select {
+		case <-readier.Ready():
+			close(ready)
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// runInline runs the only runner of an app in the calling goroutine sparing the machinery of
+// running many, and reports the errors of the runner and its sub-runners (see the Spawner type) to
+// the sink.
+func (a App) runInline(
+	ctx context.Context,
+	sink *errorSink,
+	runner *managedRunner,
+	ready map[reflect.Type]chan struct{},
+	runnerReady chan struct{},
+	options options,
+) {
+	a.spawner.start(ctx, sink.reportFatal)
+	err := runner.runManaged(ctx, ready, runnerReady, options.stagger.delay(0), options)
+	if err != nil {
+		sink.report(err)
+	}
+	a.spawner.finish()
+}
+
+// runConcurrently runs the runners of an app in goroutines of their own, and reports the errors of
+// the runners, their sub-runners (see the Spawner type) and the smoke run, if any, to the sink.
+func (a App) runConcurrently(
+	ctx context.Context,
+	cancel func(),
+	sink *errorSink,
+	runners []*managedRunner,
+	ready map[reflect.Type]chan struct{},
+	readiness []chan struct{},
+	options options,
+) {
+	var finished sync.WaitGroup
+	a.spawner.start(ctx, sink.reportFatal)
+	if options.smokeRun > 0 {
+		finished.Add(1)
+		go func() {
+			defer finished.Done()
+			if err := smokeRun(ctx, cancel, options.smokeRun, runners, readiness); err != nil {
+				sink.report(err)
+			}
+		}()
+	}
+	finished.Add(len(runners))
+	for i, runner := range runners {
+		go func(runner *managedRunner, runnerReady chan struct{}, delay time.Duration) {
+			defer finished.Done()
+			if err := runner.runManaged(ctx, ready, runnerReady, delay, options); err != nil {
+				sink.report(err)
+			}
+		}(runner, readiness[i], options.stagger.delay(i))
+	}
+	finished.Wait()
+	a.spawner.finish()
+}
+
+// runManaged runs the runner once its start is due: after the delay and once the runners it's to
+// start after are ready. The callbacks of the options are invoked once the runner exits for good.
+func (r *managedRunner) runManaged(
+	ctx context.Context,
+	ready map[reflect.Type]chan struct{},
+	runnerReady chan struct{},
+	delay time.Duration,
+	options options,
+) error {
+	defer func() {
+		for _, callback := range options.runnerExit {
+			callback(r.report())
+		}
+	}()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			r.setState(RunnerExited, nil)
+			r.stop(ctx)
+
+			return nil
+		}
+	}
+	for _, prerequisite := range r.startAfter {
+		prerequisiteReady, ok := ready[prerequisite]
+		if !ok {
+			continue
+		}
+
+		select {
+		case <-prerequisiteReady:
+		case <-ctx.Done():
+			r.setState(RunnerExited, nil)
+			r.stop(ctx)
+
+			return nil
+		}
+	}
+	r.signalReady(ctx, runnerReady)
+	var err error
+	if options.monitored() {
+		pprof.Do(ctx, pprof.Labels(runnerLabel, r.name()), func(ctx context.Context) {
+			err = r.run(ctx, options.restartPolicy, options.runMiddleware)
+		})
+	} else {
+		err = r.run(ctx, options.restartPolicy, options.runMiddleware)
+	}
+	if err != nil {
+		return fmt.Errorf("runner '%s': %w", nameOf(r.Runner), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,388 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestRestarts(t *testing.T) {
+
+	t.Run("recovers", func(t *testing.T) {
+
+		var runs int
+
+		app, err := chariot.New(chariot.With(func() A {
+
+			var a A
+			a.mocks.Run = func(context.Context) error {
+
+				runs++
+				if runs < 3 {
+					return errors.New("transient")
+				}
+
+				return nil
+			}
+
+			return a
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(chariot.WithRestarts(3, time.Minute, time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+
+		health := app.Health()
+		switch {
+		case len(health) != 1:
+			t.Fatal(health)
+		case health[0].State != chariot.RunnerExited:
+			t.Fatal(health[0].State)
+		case health[0].Restarts != 2:
+			t.Fatal(health[0].Restarts)
+		}
+	})
+
+	t.Run("circuit-breaker", func(t *testing.T) {
+
+		testErr := errors.New("test error")
+
+		app, err := chariot.New(chariot.With(
+			func() A {
+
+				var a A
+				a.mocks.Run = func(context.Context) error {
+
+					return testErr
+				}
+
+				return a
+			},
+			func() B {
+
+				return B{}
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		err = app.Run(chariot.WithRestarts(3, time.Minute, time.Millisecond))
+		switch {
+		case !errors.Is(err, chariot.ErrRunnerFailed):
+			t.Fatal(err)
+		case !errors.Is(err, testErr):
+			t.Fatal(err)
+		}
+
+		for _, health := range app.Health() {
+			switch health.Name {
+			case "chariot_test.A":
+				if health.State != chariot.RunnerFailed || health.Restarts != 2 {
+					t.Fatal(health)
+				}
+			case "chariot_test.B":
+				if health.State != chariot.RunnerExited {
+					t.Fatal(health)
+				}
+			default:
+				t.Fatal(health)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		for _, option := range []chariot.RunOption{
+			chariot.WithRestarts(0, time.Minute, time.Millisecond),
+			chariot.WithRestarts(-1, time.Minute, time.Millisecond),
+			chariot.WithRestarts(3, -time.Minute, time.Millisecond),
+			chariot.WithRestarts(3, time.Minute, -time.Millisecond),
+		} {
+			app, err := chariot.New(chariot.WithComponents(A{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := app.Run(option); err == nil {
+				t.FailNow()
+			}
+			if state := app.State(); state != chariot.AppReady {
+				t.Fatal(state)
+			}
+			app.Shutdown()
+		}
+	})
+}
+
+func TestAppRunReport(t *testing.T) {
+
+	expected := errors.New("failure")
+
+	var a A
+	a.mocks.Run = func(ctx context.Context) error {
+
+		<-ctx.Done()
+
+		return nil
+	}
+
+	var b B
+	b.mocks.Run = func(context.Context) error {
+
+		return expected
+	}
+
+	app, err := chariot.New(chariot.WithComponents(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	report := app.RunReport()
+	switch {
+	case !errors.Is(report.Err, expected):
+		t.Fatal(report.Err)
+	case !errors.Is(report.Trigger, expected):
+		t.Fatal(report.Trigger)
+	case len(report.Runners) != 2:
+		t.Fatal(report.Runners)
+	}
+
+	for _, runner := range report.Runners {
+		switch runner.Name {
+		case "chariot_test.A":
+			if runner.State != chariot.RunnerExited || !runner.Cancelled {
+				t.Fatal(runner)
+			}
+		case "chariot_test.B":
+			if runner.State != chariot.RunnerFailed || runner.Cancelled || runner.Err != expected {
+				t.Fatal(runner)
+			}
+		default:
+			t.Fatal(runner.Name)
+		}
+		if runner.Started.IsZero() {
+			t.Fatal(runner)
+		}
+	}
+}
+
+func TestRunReportQuarantine(t *testing.T) {
+
+	testErr := errors.New("test error")
+
+	var a A
+	a.mocks.Run = func(context.Context) error {
+
+		return testErr
+	}
+
+	app, err := chariot.New(chariot.WithComponents(a, B{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	report := app.RunReport(chariot.WithRestarts(2, time.Minute, time.Millisecond))
+	switch {
+	case !errors.Is(report.Err, chariot.ErrRunnerFailed):
+		t.Fatal(report.Err)
+	case report.Trigger != nil:
+		t.Fatal(report.Trigger)
+	case len(report.Quarantined) != 1 || report.Quarantined[0].Name != "chariot_test.A":
+		t.Fatal(report.Quarantined)
+	case len(report.Quarantined[0].Failures) != 2:
+		t.Fatal(report.Quarantined[0].Failures)
+	}
+
+	timeline := report.Timeline()
+	switch {
+	case len(timeline) != 2:
+		t.Fatal(timeline)
+	case timeline[0].Runner != "chariot_test.A" || timeline[0].Err != testErr:
+		t.Fatal(timeline[0])
+	case timeline[1].At.Before(timeline[0].At):
+		t.Fatal(timeline)
+	}
+}
+
+func TestRunReportFailuresRetained(t *testing.T) {
+
+	var (
+		a        A
+		failures int
+	)
+	a.mocks.Run = func(context.Context) error {
+
+		failures++
+
+		return fmt.Errorf("failure %d", failures)
+	}
+
+	app, err := chariot.New(chariot.WithComponents(a, B{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	report := app.RunReport(chariot.WithRestarts(40, time.Minute, 0))
+	if len(report.Quarantined) != 1 {
+		t.Fatal(report.Quarantined)
+	}
+
+	retained := report.Quarantined[0].Failures
+	if len(retained) != 32 {
+		t.Fatal(len(retained))
+	}
+	for i, failure := range retained {
+		if expected := fmt.Sprintf("failure %d", i+9); failure.Err.Error() != expected {
+			t.Fatal(failure.Err)
+		}
+	}
+}
+
+func TestWithStaggeredStart(t *testing.T) {
+
+	const interval = 20 * time.Millisecond
+
+	app, err := chariot.New(chariot.WithComponents(A{}, B{}, namedRunner(func(context.Context) error {
+
+		return nil
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	report := app.RunReport(chariot.WithStaggeredStart(interval, time.Millisecond))
+	if report.Err != nil {
+		t.Fatal(report.Err)
+	}
+
+	for i := 1; i < len(report.Runners); i++ {
+		gap := report.Runners[i].Started.Sub(report.Runners[0].Started)
+		if gap < time.Duration(i)*interval-time.Millisecond {
+			t.Fatal(i, gap)
+		}
+	}
+}
+
+func TestWithSmokeRun(t *testing.T) {
+
+	run := func(ctx context.Context) error {
+
+		<-ctx.Done()
+
+		return nil
+	}
+
+	t.Run("ready", func(t *testing.T) {
+
+		var a A
+		a.mocks.Run = run
+
+		app, err := chariot.New(chariot.WithComponents(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(chariot.WithSmokeRun(10 * time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("not-ready", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.WithComponents(&readyRunner{
+			ready: make(chan struct{}),
+			run:   run,
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		err = app.Run(chariot.WithSmokeRun(10 * time.Millisecond))
+		if !errors.Is(err, chariot.ErrNotReady) {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestWithRunnerExit(t *testing.T) {
+
+	testErr := errors.New("test error")
+
+	var a A
+	a.mocks.Run = func(context.Context) error {
+
+		return testErr
+	}
+
+	app, err := chariot.New(chariot.WithComponents(a, B{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	var (
+		mu      sync.Mutex
+		reports = make(map[string]chariot.RunnerReport)
+	)
+	err = app.Run(
+		chariot.WithRestarts(2, time.Minute, time.Millisecond),
+		chariot.WithRunnerExit(func(report chariot.RunnerReport) {
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			reports[report.Name] = report
+		}),
+	)
+	if !errors.Is(err, testErr) {
+		t.Fatal(err)
+	}
+
+	switch failed, exited := reports["chariot_test.A"], reports["chariot_test.B"]; {
+	case len(reports) != 2:
+		t.Fatal(reports)
+	case !errors.Is(failed.Err, testErr) || failed.State != chariot.RunnerFailed:
+		t.Fatal(failed)
+	case exited.Err != nil || exited.State != chariot.RunnerExited:
+		t.Fatal(exited)
+	}
+}
@@ -0,0 +1,45 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+// Scope makes a child app, which inherits every component of the app and is initialized with the
+// given options on top of it. Components of the scope are able to depend on the inherited ones,
+// whereas the app stays unaware of the scope. A scope must not provide a component the app
+// already has, except for context.Context, which a scope has its own of, derived from the app's,
+// and the components of constructors annotated with Override.
+//
+// A scope is shut down on its own, invoking only the shutdowners of its components, and is meant
+// to be short-lived, e.g. to serve a single request. Scope reports an error if the app isn't
+// valid or has been shut down.
+func (a App) Scope(funcOptions ...Option) (App, error) {
+	if !a.Valid() || a.isClosed() {
+		return App{}, ErrAppClosed
+	}
+
+	plan, err := newPlan(a, funcOptions)
+	if err != nil {
+		return App{}, err
+	}
+
+	return plan.build(plan.options.initCtx)
+}
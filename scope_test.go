@@ -0,0 +1,273 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestAppScope(t *testing.T) {
+
+	t.Run("inherit", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func() *C {
+
+			return new(C)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		scope, err := app.Scope(chariot.With(func(c *C) *D {
+
+			if c == nil {
+				t.FailNow()
+			}
+
+			return new(D)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer scope.Shutdown()
+
+		var (
+			c1, c2 *C
+			d      *D
+		)
+		switch {
+		case !app.Retrieve(&c1):
+			t.FailNow()
+		case !scope.Retrieve(&c2):
+			t.FailNow()
+		case c1 != c2:
+			t.FailNow()
+		case !scope.Retrieve(&d):
+			t.FailNow()
+		case app.Retrieve(&d):
+			t.FailNow()
+		}
+	})
+
+	t.Run("own-context", func(t *testing.T) {
+
+		key := new(struct{})
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		ctx := context.WithValue(context.Background(), key, key)
+		if _, err := app.Scope(chariot.With(func(ctx context.Context) *C {
+
+			if value := ctx.Value(key); value != key {
+				t.Fatal(value)
+			}
+
+			return new(C)
+		}), chariot.WithInitContext(ctx)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func() *C {
+
+			return new(C)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if _, err := app.Scope(chariot.With(func() *C {
+
+			return new(C)
+		})); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+
+		var appShutdown, scopeShutdown bool
+
+		var a A
+		a.mocks.Shutdown = func(context.Context) {
+
+			appShutdown = true
+		}
+
+		app, err := chariot.New(chariot.With(func() A {
+
+			return a
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		scope, err := app.Scope(chariot.With(func(A) B {
+
+			var b B
+			b.mocks.Shutdown = func(context.Context) {
+
+				scopeShutdown = true
+			}
+
+			return b
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		scope.Shutdown()
+		if appShutdown || !scopeShutdown {
+			t.FailNow()
+		}
+		if _, err := app.Scope(); err != nil {
+			t.Fatal(err)
+		}
+
+		app.Shutdown()
+		if !appShutdown {
+			t.FailNow()
+		}
+		if _, err := app.Scope(); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+
+		var calls int
+
+		app, err := chariot.New(chariot.With(
+			func() *C {
+
+				return new(C)
+			},
+			chariot.Scoped(func(*C, context.Context) *D {
+
+				calls++
+
+				return new(D)
+			}),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var d1, d2 *D
+		switch {
+		case app.Retrieve(&d1):
+			t.FailNow()
+		case calls != 0:
+			t.Fatal(calls)
+		}
+
+		for _, d := range []**D{&d1, &d2} {
+			scope, err := app.Scope()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !scope.Retrieve(d) {
+				t.FailNow()
+			}
+			scope.Shutdown()
+		}
+
+		if calls != 2 {
+			t.Fatal(calls)
+		}
+	})
+
+	t.Run("scoped-dependent", func(t *testing.T) {
+
+		if _, err := chariot.New(chariot.With(
+			chariot.Scoped(func() *C {
+
+				return new(C)
+			}),
+			func(*C) *D {
+
+				return new(D)
+			},
+		)); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+
+		original, replacement := new(C), new(C)
+		app, err := chariot.New(chariot.WithComponents(original))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var consumed *C
+		scope, err := app.Scope(chariot.With(
+			chariot.Override(func() *C {
+
+				return replacement
+			}),
+			func(c *C) *D {
+
+				consumed = c
+
+				return new(D)
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer scope.Shutdown()
+
+		var c1, c2 *C
+		switch {
+		case consumed != replacement:
+			t.FailNow()
+		case !scope.Retrieve(&c1) || c1 != replacement:
+			t.FailNow()
+		case !app.Retrieve(&c2) || c2 != original:
+			t.FailNow()
+		}
+
+		if _, err := app.Scope(chariot.With(chariot.Override(func() *D {
+
+			return new(D)
+		}))); err == nil {
+			t.FailNow()
+		}
+	})
+}
@@ -0,0 +1,210 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// retainedResolutions is the number of the latest resolutions of a component type retained for
+// the percentiles.
+const retainedResolutions = 1024
+
+// Percentiles summarize the durations of resolutions.
+type Percentiles struct {
+	// Count is the number of resolutions recorded, including the ones no longer retained.
+	Count int
+	// P50 is the median of the retained durations.
+	P50 time.Duration
+	// P90 is the 90th percentile of the retained durations.
+	P90 time.Duration
+	// P99 is the 99th percentile of the retained durations.
+	P99 time.Duration
+	// Max is the maximum of the retained durations.
+	Max time.Duration
+}
+
+// ResolutionStats describe the way scopes resolve a component type.
+type ResolutionStats struct {
+	// Type is the type of the component.
+	Type reflect.Type
+	// Construction summarizes the time the initializer of the component took.
+	Construction Percentiles
+	// Retrieval summarizes the time the retrievals of the component took, including lazy
+	// constructions (see the Lazy function).
+	Retrieval Percentiles
+}
+
+// resolutionTracer records the durations of resolutions of an app's scopes. The scopes share the
+// tracer of the app they're opened of.
+type resolutionTracer struct {
+	mu    sync.Mutex
+	types map[reflect.Type]*resolutions
+}
+
+type resolutions struct {
+	construction durations
+	retrieval    durations
+}
+
+// durations is a ring of the latest durations.
+type durations struct {
+	retained []time.Duration
+	next     int
+	count    int
+}
+
+// WithScopeTracing makes the scopes of an app (see the Scope method) record the time they take to
+// resolve components, i.e. to construct and to retrieve them, so that the hot construction paths of
+// request handling are found with the ScopeResolutions method. The latest 1024 resolutions of each
+// component type are retained. The option has no effect on scopes.
+func WithScopeTracing() Option {
+	return func(options *options) {
+		options.scopeTracing = true
+	}
+}
+
+// ScopeResolutions reports the resolutions recorded by the scopes of the app, or of the app the
+// scope is opened of, with the WithScopeTracing option provided; otherwise, it reports nothing.
+// The hottest component types go first, i.e. the stats are ordered by the greater of the 99th
+// percentiles descending.
+func (a App) ScopeResolutions() []ResolutionStats {
+	if a.tracer == nil {
+		return nil
+	}
+
+	return a.tracer.stats()
+}
+
+// traceConstruction records the duration of a construction performed by a scope.
+func (a App) traceConstruction(constructor *node, took time.Duration) {
+	if a.tracer == nil || !a.parent.Valid() {
+		return
+	}
+
+	for _, componentType := range constructor.signature.components {
+		a.tracer.record(componentType, took, func(r *resolutions) *durations {
+			return &r.construction
+		})
+	}
+}
+
+// traceRetrieval records the duration of a retrieval performed via a scope, and logs the retrieval
+// if resolutions are to be logged (see the DebugToggles type).
+func (a App) traceRetrieval(componentType reflect.Type, started time.Time) {
+	if a.toggles.Verbose() {
+		a.logf("retrieved '%s' in %s\n", componentType, time.Since(started))
+	}
+	if a.tracer == nil || !a.parent.Valid() {
+		return
+	}
+
+	a.tracer.record(componentType, time.Since(started), func(r *resolutions) *durations {
+		return &r.retrieval
+	})
+}
+
+func (t *resolutionTracer) record(
+	componentType reflect.Type,
+	took time.Duration,
+	kind func(*resolutions) *durations,
+) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.types == nil {
+		t.types = make(map[reflect.Type]*resolutions)
+	}
+	recorded, found := t.types[componentType]
+	if !found {
+		recorded = new(resolutions)
+		t.types[componentType] = recorded
+	}
+	kind(recorded).add(took)
+}
+
+func (t *resolutionTracer) stats() []ResolutionStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]ResolutionStats, 0, len(t.types))
+	for componentType, recorded := range t.types {
+		stats = append(stats, ResolutionStats{
+			Type:         componentType,
+			Construction: recorded.construction.percentiles(),
+			Retrieval:    recorded.retrieval.percentiles(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		hottest := func(stats ResolutionStats) time.Duration {
+			if stats.Construction.P99 > stats.Retrieval.P99 {
+				return stats.Construction.P99
+			}
+
+			return stats.Retrieval.P99
+		}
+		if hottest(stats[i]) != hottest(stats[j]) {
+			return hottest(stats[i]) > hottest(stats[j])
+		}
+
+		return stats[i].Type.String() < stats[j].Type.String()
+	})
+
+	return stats
+}
+
+func (d *durations) add(took time.Duration) {
+	d.count++
+	if len(d.retained) < retainedResolutions {
+		d.retained = append(d.retained, took)
+
+		return
+	}
+	d.retained[d.next] = took
+	d.next = (d.next + 1) % retainedResolutions
+}
+
+// percentiles computes the percentiles of the retained durations by the nearest-rank method.
+func (d *durations) percentiles() Percentiles {
+	percentiles := Percentiles{Count: d.count}
+	if len(d.retained) == 0 {
+		return percentiles
+	}
+
+	sorted := append([]time.Duration(nil), d.retained...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	rank := func(percentile int) time.Duration {
+		return sorted[(percentile*len(sorted)+99)/100-1]
+	}
+	percentiles.P50 = rank(50)
+	percentiles.P90 = rank(90)
+	percentiles.P99 = rank(99)
+	percentiles.Max = sorted[len(sorted)-1]
+
+	return percentiles
+}
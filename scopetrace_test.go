@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithScopeTracing(t *testing.T) {
+
+	t.Run("percentiles", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func() *C {
+
+			return new(C)
+		}), chariot.WithScopeTracing())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		for i := 0; i < 3; i++ {
+			scope, err := app.Scope(chariot.With(func(*C) *D {
+
+				time.Sleep(time.Millisecond)
+
+				return new(D)
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var c *C
+			if !scope.Retrieve(&c) {
+				t.FailNow()
+			}
+			scope.Shutdown()
+		}
+
+		var c *C
+		if !app.Retrieve(&c) {
+			t.FailNow()
+		}
+
+		stats := app.ScopeResolutions()
+		if len(stats) != 2 {
+			t.Fatal(stats)
+		}
+
+		d := stats[0]
+		switch {
+		case d.Type != reflect.TypeOf((*D)(nil)):
+			t.Fatal(d)
+		case d.Construction.Count != 3 || d.Construction.P50 < time.Millisecond:
+			t.Fatal(d)
+		case d.Construction.Max < d.Construction.P99 || d.Construction.P99 < d.Construction.P50:
+			t.Fatal(d)
+		case d.Retrieval.Count != 0:
+			t.Fatal(d)
+		}
+
+		c1 := stats[1]
+		if c1.Type != reflect.TypeOf((*C)(nil)) || c1.Retrieval.Count != 3 || c1.Construction.Count != 0 {
+			t.Fatal(c1)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		scope, err := app.Scope(chariot.WithComponents(new(C)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer scope.Shutdown()
+
+		var c *C
+		if !scope.Retrieve(&c) || app.ScopeResolutions() != nil || scope.ScopeResolutions() != nil {
+			t.FailNow()
+		}
+	})
+}
@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Server adapts a server started and stopped by a pair of functions to both the Runner and the
+// Shutdowner interfaces (see the ServeCloser function).
+type Server struct {
+	start func() error
+	stop  func(context.Context) error
+
+	once     sync.Once
+	stopping chan struct{}
+}
+
+// ServeCloser makes a Server of the functions serving until stopped and stopping gracefully, e.g.
+// the ListenAndServe and Shutdown methods of http.Server, or the Serve and GracefulStop ones of a
+// gRPC server wrapped accordingly. The Run method of the Server invokes the start function, and
+// stops the server once the context passed to it is cancelled, as start functions don't watch
+// contexts. The server is stopped at most once, either by the Run or the Shutdown method; an error
+// the start function returns once the server is being stopped, e.g. http.ErrServerClosed, is
+// deemed a graceful exit.
+func ServeCloser(start func() error, stop func(context.Context) error) *Server {
+	return &Server{
+		start:    start,
+		stop:     stop,
+		stopping: make(chan struct{}),
+	}
+}
+
+// Run serves until the server is stopped either on its own, after the context is cancelled, or by
+// the Shutdown method. The error of stopping the server after the context is cancelled is returned.
+func (s *Server) Run(ctx context.Context) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- s.start()
+	}()
+
+	select {
+	case err := <-served:
+		select {
+		case <-s.stopping:
+			return nil
+		default:
+			return err
+		}
+	case <-ctx.Done():
+		err := s.halt(context.WithoutCancel(ctx))
+		<-served
+
+		return err
+	case <-s.stopping:
+		<-served
+
+		return nil
+	}
+}
+
+// Shutdown stops the server unless it has been stopped already. An error stopping it is logged.
+func (s *Server) Shutdown(ctx context.Context) {
+	if err := s.halt(ctx); err != nil {
+		logError(ctx, fmt.Errorf("stopping server: %w", err))
+	}
+}
+
+// halt stops the server once and reports the error of stopping it to the first caller only.
+func (s *Server) halt(ctx context.Context) error {
+	var err error
+	s.once.Do(func() {
+		close(s.stopping)
+		err = s.stop(ctx)
+	})
+
+	return err
+}
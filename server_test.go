@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestServeCloser(t *testing.T) {
+
+	newServer := func(t *testing.T) *chariot.Server {
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := http.Server{
+			Handler: http.NotFoundHandler(),
+		}
+
+		return chariot.ServeCloser(func() error {
+
+			return server.Serve(listener)
+		}, server.Shutdown)
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func() *chariot.Server {
+
+			return newServer(t)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		runErr := make(chan error, 1)
+		go func() {
+
+			runErr <- app.Run(chariot.WithRunContext(ctx))
+		}()
+		<-app.Ready()
+		cancel()
+
+		if err := <-runErr; err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+
+		server := newServer(t)
+
+		runErr := make(chan error, 1)
+		go func() {
+
+			runErr <- server.Run(context.Background())
+		}()
+		server.Shutdown(context.Background())
+
+		if err := <-runErr; err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		expected := errors.New("failure")
+		server := chariot.ServeCloser(func() error {
+
+			return expected
+		}, func(context.Context) error {
+
+			return nil
+		})
+
+		if err := server.Run(context.Background()); !errors.Is(err, expected) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package sidecar is an experimental bridge injecting a component whose implementation lives in a
+// separate process, so that a risky plugin, or one written in another language, is isolated from
+// an app. The sidecar serves the implementation over a unix socket with net/rpc (see the Serve
+// function), while the app is provided with a Client to the socket, optionally starting the
+// sidecar process itself and failing the run once it exits (see the Module function). A component
+// is exposed locally by a thin proxy delegating to the client:
+//
+//	type Renderer struct {
+//		client *sidecar.Client
+//	}
+//
+//	func (r Renderer) Render(ctx context.Context, page Page) (string, error) {
+//		var html string
+//		err := r.client.Call(ctx, "Renderer.Render", page, &html)
+//
+//		return html, err
+//	}
+//
+// The proxy is then registered like any other component, e.g. chariot.With(func(client
+// *sidecar.Client) Renderer { return Renderer{client} }).
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+// EnvSocket is the environment variable passing the path of the socket to a sidecar process
+// started by an app.
+const EnvSocket = "CHARIOT_SIDECAR_SOCKET"
+
+// dialInterval is the interval the socket is dialed at till the sidecar listens on it.
+const dialInterval = 10 * time.Millisecond
+
+// Client is a Runner- and Shutdowner-conformant component calling a sidecar. The run fails once a
+// sidecar process started by the client exits; the process is interrupted, and killed if it
+// doesn't exit in time, once the client is shut down.
+type Client struct {
+	rpc     *rpc.Client
+	process *exec.Cmd
+	exited  chan struct{}
+	err     error
+}
+
+// ErrNoSocket is returned by the Listen function when a process isn't started as a sidecar.
+var ErrNoSocket = errors.New("sidecar socket isn't passed")
+
+// Module provides a client to the sidecar listening on the unix socket at the path to an app. If
+// the command isn't nil, the sidecar process is started with the path passed via EnvSocket. The
+// socket is dialed till the sidecar listens on it, bounded by the context of the initialization.
+func Module(path string, command *exec.Cmd) chariot.Module {
+	return chariot.With(func(ctx context.Context) (*Client, error) {
+		return Start(ctx, path, command)
+	})
+}
+
+// Start makes a client to the sidecar listening on the unix socket at the path, starting the
+// sidecar process first if the command isn't nil.
+func Start(ctx context.Context, path string, command *exec.Cmd) (*Client, error) {
+	client := Client{
+		process: command,
+		exited:  make(chan struct{}),
+	}
+	if command != nil {
+		// A socket left over by a previous sidecar would be dialed otherwise.
+		os.Remove(path)
+		if command.Env == nil {
+			command.Env = os.Environ()
+		}
+		command.Env = append(command.Env, EnvSocket+"="+path)
+		if err := command.Start(); err != nil {
+			return nil, fmt.Errorf("starting sidecar: %w", err)
+		}
+		go func() {
+			client.err = command.Wait()
+			close(client.exited)
+		}()
+	}
+
+	conn, err := client.dial(ctx, path)
+	if err != nil {
+		client.stop(ctx)
+
+		return nil, err
+	}
+	client.rpc = rpc.NewClient(conn)
+
+	return &client, nil
+}
+
+// Call invokes the method of the sidecar, e.g. "Renderer.Render", with the arguments and waits
+// for the reply. If the context is done first, the context's error is returned, and the reply may
+// still get written to afterwards.
+func (c *Client) Call(ctx context.Context, method string, args, reply interface{}) error {
+	call := c.rpc.Go(method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run waits for the sidecar process to exit, failing once it does, or the context to be done.
+func (c *Client) Run(ctx context.Context) error {
+	if c.process == nil {
+		<-ctx.Done()
+
+		return nil
+	}
+
+	select {
+	case <-c.exited:
+		if c.err != nil {
+			return fmt.Errorf("sidecar exited: %w", c.err)
+		}
+
+		return errors.New("sidecar exited")
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// Shutdown closes the connection to the sidecar and stops the sidecar process, if it was started
+// by the client.
+func (c *Client) Shutdown(ctx context.Context) {
+	c.rpc.Close()
+	c.stop(ctx)
+}
+
+// dial dials the socket till the sidecar listens on it, the sidecar process exits, or the context
+// is done.
+func (c *Client) dial(ctx context.Context, path string) (net.Conn, error) {
+	ticker := time.NewTicker(dialInterval)
+	defer ticker.Stop()
+
+	for {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			return conn, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-c.exited:
+			return nil, fmt.Errorf("sidecar exited before listening: %w", err)
+		case <-ctx.Done():
+			return nil, fmt.Errorf("dialing sidecar: %w", errors.Join(ctx.Err(), err))
+		}
+	}
+}
+
+// stop interrupts the sidecar process and kills it unless it exits till the context is done.
+func (c *Client) stop(ctx context.Context) {
+	if c.process == nil {
+		return
+	}
+
+	if err := c.process.Process.Signal(os.Interrupt); err != nil {
+		c.process.Process.Kill()
+	}
+	select {
+	case <-c.exited:
+	case <-ctx.Done():
+		c.process.Process.Kill()
+		<-c.exited
+	}
+}
+
+// Listen listens on the unix socket passed to a sidecar process via EnvSocket.
+func Listen() (net.Listener, error) {
+	path := os.Getenv(EnvSocket)
+	if path == "" {
+		return nil, ErrNoSocket
+	}
+
+	return net.Listen("unix", path)
+}
+
+// Serve serves the methods of the receiver conforming to net/rpc, e.g. func (r *Renderer)
+// Render(page Page, html *string) error, under the name on the listener till it's closed.
+func Serve(listener net.Listener, name string, receiver interface{}) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(name, receiver); err != nil {
+		return err
+	}
+	server.Accept(listener)
+
+	return nil
+}
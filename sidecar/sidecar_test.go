@@ -0,0 +1,176 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sidecar_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/sidecar"
+)
+
+type (
+	Echo struct{}
+
+	echo struct {
+		client *sidecar.Client
+	}
+)
+
+func (Echo) Upper(in string, out *string) error {
+
+	*out = strings.ToUpper(in)
+
+	return nil
+}
+
+func (Echo) Exit(code int, _ *struct{}) error {
+
+	os.Exit(code)
+
+	return nil
+}
+
+func (e echo) upper(ctx context.Context, in string) (string, error) {
+
+	var out string
+	err := e.client.Call(ctx, "Echo.Upper", in, &out)
+
+	return out, err
+}
+
+func newEcho(client *sidecar.Client) echo {
+
+	return echo{client: client}
+}
+
+func socketPath(t *testing.T) string {
+
+	dir, err := os.MkdirTemp("", "sidecar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+
+		os.RemoveAll(dir)
+	})
+
+	return filepath.Join(dir, "echo.sock")
+}
+
+func TestModule(t *testing.T) {
+
+	t.Run("in-process", func(t *testing.T) {
+
+		path := socketPath(t)
+		if _, err := sidecar.Listen(); !errors.Is(err, sidecar.ErrNoSocket) {
+			t.Fatal(err)
+		}
+		listener, err := net.Listen("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+		go sidecar.Serve(listener, "Echo", Echo{})
+
+		app, err := chariot.New(sidecar.Module(path, nil), chariot.With(newEcho))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var e echo
+		if !app.Retrieve(&e) {
+			t.FailNow()
+		}
+		if out, err := e.upper(context.Background(), "hi"); err != nil || out != "HI" {
+			t.Fatal(out, err)
+		}
+	})
+
+	t.Run("process", func(t *testing.T) {
+
+		command := exec.Command(os.Args[0], "-test.run=TestSidecar$")
+		command.Env = append(os.Environ(), "SIDECAR_CHILD=1")
+
+		app, err := chariot.New(
+			sidecar.Module(socketPath(t), command),
+			chariot.With(newEcho),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var e echo
+		if !app.Retrieve(&e) {
+			t.FailNow()
+		}
+		if out, err := e.upper(context.Background(), "hi"); err != nil || out != "HI" {
+			t.Fatal(out, err)
+		}
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run()
+		}()
+		<-app.Ready()
+
+		e.client.Call(context.Background(), "Echo.Exit", 3, new(struct{}))
+		if err := <-ran; err == nil || !strings.Contains(err.Error(), "sidecar exited") {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestSidecar(t *testing.T) {
+
+	if os.Getenv("SIDECAR_CHILD") == "" {
+		t.Skip("run by the parent process")
+	}
+
+	listener, err := sidecar.Listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	go func() {
+
+		<-interrupted
+		listener.Close()
+	}()
+
+	if err := sidecar.Serve(listener, "Echo", Echo{}); err != nil {
+		t.Fatal(err)
+	}
+}
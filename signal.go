@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !js && !wasip1
+// +build !js,!wasip1
+
+package chariot
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+// notifyContext makes a prepackaged context out of the base one cancelled upon an interrupt or any
+// of the signals, once the callback has been invoked.
+func notifyContext(
+	base context.Context,
+	signals []os.Signal,
+	signalled func(),
+) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(base)
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, append(signals, os.Interrupt)...)
+	go func() {
+		select {
+		case <-received:
+			signalled()
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(received)
+		cancel()
+	}
+}
@@ -0,0 +1,158 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+)
+
+// Signals delivers signals received by the process to the components subscribed to them, e.g. to
+// bump a log level upon SIGUSR1, so that components don't install signal handlers of their own
+// racing with the ones of an app. An app is prepackaged with the component; subscriptions are
+// stopped once the app is shut down, closing the channels of the subscribers.
+type Signals struct {
+	mu          sync.Mutex
+	received    map[os.Signal]chan os.Signal
+	subscribed  map[os.Signal]int
+	subscribers map[*signalSubscriber]struct{}
+	stopped     bool
+}
+
+type signalSubscriber struct {
+	signals  map[os.Signal]bool
+	received chan os.Signal
+}
+
+var signalsType = reflect.TypeOf((*Signals)(nil))
+
+// Notify subscribes to the signals. The signals are delivered to the channel returned without
+// blocking, so one arriving while the previous one is still pending in the channel is dropped. The
+// function returned cancels the subscription; the default handling of a signal is restored once
+// no subscription to it is left. Unlike signal.Notify, no signals means no subscription rather
+// than one to all the signals, so the default handling of the ones the app doesn't subscribe to,
+// e.g. SIGTERM, isn't disabled. The channel is closed once the app is shut down.
+func (s *Signals) Notify(signals ...os.Signal) (<-chan os.Signal, func()) {
+	subscriber := signalSubscriber{
+		signals:  make(map[os.Signal]bool, len(signals)),
+		received: make(chan os.Signal, 1),
+	}
+	for _, sig := range signals {
+		subscriber.signals[sig] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		close(subscriber.received)
+		return subscriber.received, func() {}
+	}
+	if len(signals) == 0 {
+		return subscriber.received, func() {}
+	}
+	if s.subscribers == nil {
+		s.received = make(map[os.Signal]chan os.Signal)
+		s.subscribed = make(map[os.Signal]int)
+		s.subscribers = make(map[*signalSubscriber]struct{})
+	}
+	s.subscribers[&subscriber] = struct{}{}
+	for sig := range subscriber.signals {
+		s.subscribed[sig]++
+		if s.received[sig] != nil {
+			continue
+		}
+
+		// Every signal is received on a channel of its own, so that one can be stopped without
+		// affecting the others.
+		received := make(chan os.Signal, 1)
+		s.received[sig] = received
+		signal.Notify(received, sig)
+		go s.deliver(received)
+	}
+
+	return subscriber.received, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, found := s.subscribers[&subscriber]; !found {
+			return
+		}
+		delete(s.subscribers, &subscriber)
+
+		for sig := range subscriber.signals {
+			if s.subscribed[sig]--; s.subscribed[sig] > 0 {
+				continue
+			}
+
+			signal.Stop(s.received[sig])
+			close(s.received[sig])
+			delete(s.received, sig)
+			delete(s.subscribed, sig)
+		}
+	}
+}
+
+// deliver fans the signals received out to the subscribers.
+func (s *Signals) deliver(received <-chan os.Signal) {
+	for sig := range received {
+		s.mu.Lock()
+		for subscriber := range s.subscribers {
+			if !subscriber.signals[sig] {
+				continue
+			}
+
+			select {
+			case subscriber.received <- sig:
+			default:
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// stop stops delivering signals and closes the channels of the subscribers.
+func (s *Signals) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	for sig, received := range s.received {
+		signal.Stop(received)
+		close(received)
+		delete(s.received, sig)
+	}
+	for subscriber := range s.subscribers {
+		close(subscriber.received)
+		delete(s.subscribers, subscriber)
+	}
+}
+
+func (a App) setSignalsComponent() {
+	a.signals = new(Signals)
+	a.components[signalsType] = &component{
+		value: reflect.ValueOf(a.signals),
+	}
+}
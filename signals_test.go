@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !windows && !js && !wasip1 && !plan9
+// +build !windows,!js,!wasip1,!plan9
+
+package chariot_test
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestSignals(t *testing.T) {
+
+	var received <-chan os.Signal
+
+	app, err := chariot.New(chariot.With(func(signals *chariot.Signals) *C {
+
+		received, _ = signals.Notify(syscall.SIGUSR1)
+
+		return new(C)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case sig := <-received:
+		if sig != syscall.SIGUSR1 {
+			t.Fatal(sig)
+		}
+	case <-time.After(time.Second):
+		t.FailNow()
+	}
+
+	app.Shutdown()
+	select {
+	case _, ok := <-received:
+		if ok {
+			t.FailNow()
+		}
+	case <-time.After(time.Second):
+		t.FailNow()
+	}
+}
+
+const signalsScenarioEnv = "CHARIOT_TEST_SIGNALS_SCENARIO"
+
+func TestSignalsCancel(t *testing.T) {
+
+	if _, ok := os.LookupEnv(signalsScenarioEnv); ok {
+		app, err := chariot.New(chariot.With(func(signals *chariot.Signals) *C {
+
+			_, first := signals.Notify(syscall.SIGTERM)
+			_, second := signals.Notify(syscall.SIGTERM, syscall.SIGUSR1)
+			first()
+			second()
+
+			return new(C)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Second)
+
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSignalsCancel$")
+	cmd.Env = append(os.Environ(), signalsScenarioEnv+"=1")
+	err := cmd.Run()
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatal(err)
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); !ok || status.Signal() != syscall.SIGTERM {
+		t.Fatal(err)
+	}
+}
+
+func TestSignalsNotifyNothing(t *testing.T) {
+
+	if _, ok := os.LookupEnv(signalsScenarioEnv); ok {
+		app, err := chariot.New(chariot.With(func(signals *chariot.Signals) *C {
+
+			signals.Notify()
+
+			return new(C)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Second)
+
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSignalsNotifyNothing$")
+	cmd.Env = append(os.Environ(), signalsScenarioEnv+"=1")
+	err := cmd.Run()
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatal(err)
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); !ok || status.Signal() != syscall.SIGTERM {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// signature is the result of the analysis of an initializer's type. Initializers of the same type
+// and annotated alike share it.
+type signature struct {
+	dependencies []reflect.Type
+	variadic     reflect.Type
+	components   []reflect.Type
+	errorAt      int
+}
+
+type signatureKey struct {
+	funcType reflect.Type
+	errorAt  int
+}
+
+type signatureResult struct {
+	signature *signature
+	err       error
+}
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+	signatures sync.Map
+)
+
+// signatureOf analyses a function type or retrieves the result of a previous analysis, so
+// processes building many apps don't pay the reflection cost repeatedly. Unless annotated
+// otherwise, an error is expected to be the last returned value; an error-conformant value
+// returned in any other position makes the signature ambiguous.
+func signatureOf(funcType reflect.Type, errorAt int) (*signature, error) {
+	key := signatureKey{
+		funcType: funcType,
+		errorAt:  errorAt,
+	}
+	if cached, ok := signatures.Load(key); ok {
+		result := cached.(signatureResult)
+
+		return result.signature, result.err
+	}
+
+	analysed, err := analyseSignature(funcType, errorAt)
+	cached, _ := signatures.LoadOrStore(key, signatureResult{
+		signature: analysed,
+		err:       err,
+	})
+	result := cached.(signatureResult)
+
+	return result.signature, result.err
+}
+
+func analyseSignature(funcType reflect.Type, errorAt int) (*signature, error) {
+	analysed := signature{
+		errorAt: errorAt,
+	}
+
+	num := funcType.NumIn()
+	if funcType.IsVariadic() {
+		num--
+		analysed.variadic = funcType.In(num).Elem()
+	}
+	for i := 0; i < num; i++ {
+		analysed.dependencies = append(analysed.dependencies, funcType.In(i))
+	}
+
+	num = funcType.NumOut()
+	switch {
+	case errorAt == autoErrorAt:
+		analysed.errorAt = noError
+		if last := num - 1; last >= 0 && funcType.Out(last).Implements(errorType) {
+			analysed.errorAt = last
+		}
+
+		for i := 0; i < num; i++ {
+			if i != analysed.errorAt && funcType.Out(i).Implements(errorType) {
+				return nil, errors.New(
+					"has an ambiguous signature: an error-conformant value isn't returned last; " +
+						"annotate it with either ErrorAt or NoError",
+				)
+			}
+		}
+	case errorAt < noError:
+		return nil, errors.New("is annotated with an error at a negative position")
+	case errorAt >= num:
+		return nil, fmt.Errorf("is annotated with an error at #%d but returns %d values", errorAt, num)
+	case errorAt != noError && !funcType.Out(errorAt).Implements(errorType):
+		return nil, fmt.Errorf("is annotated with an error at #%d that isn't an error", errorAt)
+	}
+
+	for i := 0; i < num; i++ {
+		if i != analysed.errorAt {
+			analysed.components = append(analysed.components, funcType.Out(i))
+		}
+	}
+
+	return &analysed, nil
+}
+
+// split separates components from an error among values returned by an initializer.
+func (s *signature) split(outs []reflect.Value) ([]reflect.Value, error) {
+	if s.errorAt == noError {
+		return outs, nil
+	}
+
+	if err := outs[s.errorAt]; !err.IsNil() {
+		return nil, err.Interface().(error)
+	}
+
+	return append(outs[:s.errorAt:s.errorAt], outs[s.errorAt+1:]...), nil
+}
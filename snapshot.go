@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Snapshot holds components of an app constructed by constructors annotated with SnapshotSafe, to
+// be restored into other apps (see the WithSnapshot option), e.g. to speed up the startup of tests
+// building the same app over and over again.
+type Snapshot struct {
+	components map[reflect.Type]reflect.Value
+}
+
+// SnapshotSafe annotates a constructor as a deterministic one constructing pure, config-like
+// components, e.g. parsed settings or a compiled template, so that its components may be
+// snapshotted and restored instead of invoking it anew (see the Snapshot method). Restored
+// components are shared by the apps, so they mustn't be mutated. The result is to be provided in
+// place of the constructor.
+func SnapshotSafe(constructor interface{}) interface{} {
+	return annotate(constructor, func(annotation *annotation) {
+		annotation.snapshot = true
+	})
+}
+
+// WithSnapshot restores the components of a snapshot: a constructor annotated with SnapshotSafe all
+// the components of which the snapshot holds isn't invoked, and its components are taken from the
+// snapshot instead. The dependencies of the constructor are still constructed the usual way.
+func WithSnapshot(snapshot Snapshot) Option {
+	return func(options *options) {
+		if options.snapshot == nil {
+			options.snapshot = make(map[reflect.Type]reflect.Value, len(snapshot.components))
+		}
+		for componentType, value := range snapshot.components {
+			options.snapshot[componentType] = value
+		}
+	}
+}
+
+// Snapshot captures the components of the app constructed by constructors annotated with
+// SnapshotSafe. Lazy components that haven't been constructed yet, as well as Runner- and
+// Shutdowner-conformant ones, whose lifecycle is bound to the app, are left out.
+func (a App) Snapshot() Snapshot {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	snapshot := Snapshot{
+		components: make(map[reflect.Type]reflect.Value),
+	}
+	for componentType, component := range a.components {
+		if !component.snapshotSafe || !component.value.IsValid() {
+			continue
+		}
+		switch component.value.Interface().(type) {
+		case Runner, Shutdowner:
+			continue
+		}
+		snapshot.components[componentType] = component.value
+	}
+
+	return snapshot
+}
+
+// Types reports the types of the components of the snapshot ordered by their names.
+func (s Snapshot) Types() []reflect.Type {
+	types := make([]reflect.Type, 0, len(s.components))
+	for componentType := range s.components {
+		types = append(types, componentType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+
+	return types
+}
+
+// restore reports the components of a constructor annotated with SnapshotSafe restored from the
+// snapshot the app is provided with, if the snapshot holds all of them.
+func (a App) restore(constructor *node) ([]reflect.Value, bool) {
+	if !constructor.snapshotSafe || len(a.snapshot) == 0 {
+		return nil, false
+	}
+
+	outs := make([]reflect.Value, 0, len(constructor.signature.components))
+	for _, componentType := range constructor.signature.components {
+		value, ok := a.snapshot[componentType]
+		if !ok {
+			return nil, false
+		}
+		outs = append(outs, value)
+	}
+
+	return outs, true
+}
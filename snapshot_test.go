@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestSnapshot(t *testing.T) {
+
+	var calls int
+	constructors := chariot.With(
+		chariot.SnapshotSafe(func() *C {
+
+			calls++
+
+			return new(C)
+		}),
+		chariot.SnapshotSafe(chariot.Lazy(func(*C) *D {
+
+			calls++
+
+			return new(D)
+		})),
+		func() *F {
+
+			return new(F)
+		},
+	)
+
+	app, err := chariot.New(constructors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	snapshot := app.Snapshot()
+	expected := []reflect.Type{reflect.TypeOf((*C)(nil))}
+	if !reflect.DeepEqual(snapshot.Types(), expected) {
+		t.Fatal(snapshot.Types())
+	}
+
+	var d *D
+	if !app.Retrieve(&d) {
+		t.FailNow()
+	}
+	snapshot = app.Snapshot()
+	if len(snapshot.Types()) != 2 || calls != 2 {
+		t.Fatal(snapshot.Types(), calls)
+	}
+
+	restored, err := chariot.New(constructors, chariot.WithSnapshot(snapshot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Shutdown()
+
+	var (
+		c1, c2 *C
+		d2     *D
+	)
+	switch {
+	case !app.Retrieve(&c1) || !restored.Retrieve(&c2) || c1 != c2:
+		t.FailNow()
+	case !restored.Retrieve(&d2) || d2 != d:
+		t.FailNow()
+	case calls != 2:
+		t.Fatal(calls)
+	}
+}
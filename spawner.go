@@ -0,0 +1,113 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Spawner runs sub-runners on behalf of the runners of an app, e.g. a worker pool of a consumer, so
+// that their goroutines are tracked the way the runners' ones are: they're passed the runners'
+// context, the run waits for them to exit, and an error returned by any—including a recovered
+// panic—fails the run the way the one of a runner does. Sub-runners aren't restarted, so an error
+// of one cancels the run even if runners are restarted (see the WithRestarts option); the runner
+// spawning them is the one to recover from errors it tolerates. An app is prepackaged with the
+// component.
+type Spawner struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	report  func(error)
+	spawned sync.WaitGroup
+	running bool
+	crashes *crashReporter
+}
+
+var spawnerType = reflect.TypeOf((*Spawner)(nil))
+
+// Spawn runs the runner in a goroutine of its own. ErrAppNotRunning is returned unless the app is
+// running at the moment.
+func (s *Spawner) Spawn(runner Runner) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return ErrAppNotRunning
+	}
+
+	s.spawned.Add(1)
+	go func(ctx context.Context, report func(error)) {
+		defer s.spawned.Done()
+		if err := s.runSpawned(ctx, runner); err != nil {
+			report(fmt.Errorf("spawned runner '%s': %w", nameOf(runner), err))
+		}
+	}(s.ctx, s.report)
+
+	return nil
+}
+
+func (s *Spawner) runSpawned(ctx context.Context, runner Runner) (err error) {
+	if s.crashes != nil {
+		name := func() string {
+			return nameOf(runner)
+		}
+
+		return s.crashes.guard(ctx, "run", name, func() error {
+			return runner.Run(ctx)
+		})
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return runner.Run(ctx)
+}
+
+// start makes the spawner run sub-runners with the context reporting their errors to the function.
+func (s *Spawner) start(ctx context.Context, report func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ctx, s.report, s.running = ctx, report, true
+}
+
+// finish stops the spawner from running sub-runners and waits for the running ones to exit.
+func (s *Spawner) finish() {
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+
+	s.spawned.Wait()
+}
+
+func (a App) setSpawnerComponent() {
+	a.spawner = &Spawner{crashes: a.crashes}
+	a.components[spawnerType] = &component{
+		value: reflect.ValueOf(a.spawner),
+	}
+}
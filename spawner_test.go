@@ -0,0 +1,178 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+type pool struct {
+	spawner *chariot.Spawner
+	workers int
+	run     func(context.Context) error
+}
+
+func (p pool) Run(context.Context) error {
+
+	for i := 0; i < p.workers; i++ {
+		if err := p.spawner.Spawn(chariot.FuncRunner(p.run)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestSpawner(t *testing.T) {
+
+	t.Run("waited", func(t *testing.T) {
+
+		var exited int32
+
+		app, err := chariot.New(chariot.With(func(spawner *chariot.Spawner) pool {
+
+			return pool{
+				spawner: spawner,
+				workers: 3,
+				run: func(context.Context) error {
+
+					atomic.AddInt32(&exited, 1)
+
+					return nil
+				},
+			}
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if exited != 3 {
+			t.Fatal(exited)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func(spawner *chariot.Spawner) pool {
+
+			return pool{
+				spawner: spawner,
+				workers: 1,
+				run: func(context.Context) error {
+
+					panic("worker")
+				},
+			}
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("restarts", func(t *testing.T) {
+
+		testErr := errors.New("test error")
+
+		app, err := chariot.New(chariot.With(
+			func(spawner *chariot.Spawner) pool {
+
+				return pool{
+					spawner: spawner,
+					workers: 1,
+					run: func(context.Context) error {
+
+						return testErr
+					},
+				}
+			},
+			func() B {
+
+				var b B
+				b.mocks.Run = func(ctx context.Context) error {
+
+					<-ctx.Done()
+
+					return nil
+				}
+
+				return b
+			},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run(chariot.WithRestarts(3, time.Minute, time.Millisecond))
+		}()
+
+		select {
+		case err := <-ran:
+			if !errors.Is(err, testErr) {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.FailNow()
+		}
+	})
+
+	t.Run("not-running", func(t *testing.T) {
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var spawner *chariot.Spawner
+		if !app.Retrieve(&spawner) {
+			t.FailNow()
+		}
+
+		err = spawner.Spawn(chariot.FuncRunner(func(context.Context) error {
+
+			return nil
+		}))
+		if !errors.Is(err, chariot.ErrAppNotRunning) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+type readyRunner struct {
+	ready chan struct{}
+	run   func(context.Context) error
+}
+
+func TestStartAfter(t *testing.T) {
+
+	t.Run("waits-for-ready", func(t *testing.T) {
+
+		var (
+			server = readyRunner{
+				ready: make(chan struct{}),
+			}
+			serverReady bool
+			started     = make(chan bool, 1)
+		)
+		server.run = func(ctx context.Context) error {
+
+			time.Sleep(10 * time.Millisecond)
+			serverReady = true
+			close(server.ready)
+
+			return nil
+		}
+
+		app, err := chariot.New(
+			chariot.WithComponents(&server),
+			chariot.With(chariot.StartAfter(func() A {
+
+				var a A
+				a.mocks.Run = func(context.Context) error {
+
+					started <- serverReady
+
+					return nil
+				}
+
+				return a
+			}, reflect.TypeOf(&server))),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if !<-started {
+			t.FailNow()
+		}
+	})
+
+	t.Run("missing-runner", func(t *testing.T) {
+
+		app, err := chariot.New(
+			chariot.WithComponents(new(C)),
+			chariot.With(chariot.StartAfter(func() A {
+
+				return A{}
+			}, reflect.TypeOf(new(C)))),
+		)
+		if err != nil {
+			return
+		}
+		defer app.Shutdown()
+
+		t.FailNow()
+	})
+
+	t.Run("nested-missing-runner", func(t *testing.T) {
+
+		// The nodes are visited in a random order, so either runner may be validated first.
+		for i := 0; i < 10; i++ {
+			app, err := chariot.New(
+				chariot.With(
+					chariot.StartAfter(func() A {
+
+						return A{}
+					}, reflect.TypeOf(B{})),
+					chariot.StartAfter(func() B {
+
+						return B{}
+					}, reflect.TypeOf(new(readyRunner))),
+				),
+			)
+			if err != nil {
+				if !strings.Contains(err.Error(), "missing runner") {
+					t.Fatal(err)
+				}
+
+				continue
+			}
+			app.Shutdown()
+
+			t.FailNow()
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+
+		app, err := chariot.New(
+			chariot.With(
+				chariot.StartAfter(func() A {
+
+					return A{}
+				}, reflect.TypeOf(B{})),
+				chariot.StartAfter(func() B {
+
+					return B{}
+				}, reflect.TypeOf(A{})),
+			),
+		)
+		if err != nil {
+			return
+		}
+		defer app.Shutdown()
+
+		t.FailNow()
+	})
+}
+
+func (r *readyRunner) Run(ctx context.Context) error {
+
+	return r.run(ctx)
+}
+
+func (r *readyRunner) Ready() <-chan struct{} {
+
+	return r.ready
+}
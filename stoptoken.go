@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"reflect"
+	"sync"
+)
+
+// StopToken tells components an app is stopping, decoupling them from the contexts of its phases:
+// a component captures the token at construction and either watches the Done channel or defers a
+// function to the stop. The token of an app is stopped once its Shutdown method is called, before
+// the runners are waited for and the shutdowners invoked. An app is prepackaged with the
+// component; a standalone one, e.g. for a test, is made by the NewStopToken function.
+type StopToken struct {
+	mu       sync.Mutex
+	done     chan struct{}
+	stopped  bool
+	deferred []func()
+}
+
+var stopTokenType = reflect.TypeOf((*StopToken)(nil))
+
+// NewStopToken makes a standalone token along with a function stopping it. The function is safe
+// to call multiple times.
+func NewStopToken() (*StopToken, func()) {
+	token := &StopToken{
+		done: make(chan struct{}),
+	}
+
+	return token, token.stop
+}
+
+// Done returns a channel that's closed once the token is stopped.
+func (t *StopToken) Done() <-chan struct{} {
+	return t.done
+}
+
+// Stopping reports whether the token has been stopped.
+func (t *StopToken) Stopping() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defer provides a function invoked once the token is stopped. Functions are invoked in the
+// reverse order they were provided in, akin to defer statements; a function provided after the
+// token has been stopped is invoked right away.
+func (t *StopToken) Defer(f func()) {
+	t.mu.Lock()
+	if !t.stopped {
+		t.deferred = append(t.deferred, f)
+		t.mu.Unlock()
+
+		return
+	}
+	t.mu.Unlock()
+
+	f()
+}
+
+func (t *StopToken) stop() {
+	t.mu.Lock()
+	if t.stopped {
+		t.mu.Unlock()
+
+		return
+	}
+	t.stopped = true
+	close(t.done)
+	deferred := t.deferred
+	t.deferred = nil
+	t.mu.Unlock()
+
+	for i := len(deferred) - 1; i >= 0; i-- {
+		deferred[i]()
+	}
+}
+
+func (a App) setStopTokenComponent() {
+	a.stopToken, _ = NewStopToken()
+	a.components[stopTokenType] = &component{
+		value: reflect.ValueOf(a.stopToken),
+	}
+}
@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestStopToken(t *testing.T) {
+
+	t.Run("app", func(t *testing.T) {
+
+		var token *chariot.StopToken
+		app, err := chariot.New(chariot.With(func(*chariot.StopToken) *C {
+
+			return new(C)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !app.Retrieve(&token) {
+			t.FailNow()
+		}
+
+		var order []int
+		token.Defer(func() {
+
+			order = append(order, 1)
+		})
+		token.Defer(func() {
+
+			order = append(order, 2)
+		})
+		if token.Stopping() {
+			t.FailNow()
+		}
+
+		app.Shutdown()
+
+		select {
+		case <-token.Done():
+		default:
+			t.FailNow()
+		}
+		if !token.Stopping() || !reflect.DeepEqual(order, []int{2, 1}) {
+			t.Fatal(order)
+		}
+	})
+
+	t.Run("standalone", func(t *testing.T) {
+
+		token, stop := chariot.NewStopToken()
+		stop()
+		stop()
+
+		deferred := false
+		token.Defer(func() {
+
+			deferred = true
+		})
+		if !token.Stopping() || !deferred {
+			t.FailNow()
+		}
+	})
+}
@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Suspender stands for a Runner-conformant component that can be paused without exiting, e.g. a
+// consumer that stops consuming while keeping its connections warm, so that an app is idled for a
+// maintenance window or to save costs without a restart (see the Suspend method).
+type Suspender interface {
+	Suspend(context.Context) error
+	Resume(context.Context) error
+}
+
+// Suspend pauses the runners of a running app that conform to the Suspender interface, in the
+// reverse order they were collected in. Errors of the runners are aggregated, each prefixed with
+// the name of the runner that returned it. The app stays suspended regardless, until the Resume
+// method is invoked; suspending a suspended app does nothing. ErrAppNotRunning is returned unless
+// the app is running.
+func (a App) Suspend(ctx context.Context) error {
+	a.mu.Lock()
+	if a.appState != AppRunning {
+		a.mu.Unlock()
+
+		return ErrAppNotRunning
+	}
+	if a.suspended {
+		a.mu.Unlock()
+
+		return nil
+	}
+	a.suspended = true
+	runners := a.runners
+	a.mu.Unlock()
+
+	var errs []error
+	for i := len(runners) - 1; i >= 0; i-- {
+		if suspender, ok := runners[i].Runner.(Suspender); ok {
+			if err := suspender.Suspend(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("runner '%s': %w", runners[i].name(), err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Resume resumes the runners of a suspended app that conform to the Suspender interface, in the
+// order they were collected in. Errors are reported the way the Suspend method does; resuming an
+// app that isn't suspended does nothing.
+func (a App) Resume(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.suspended {
+		a.mu.Unlock()
+
+		return nil
+	}
+	a.suspended = false
+	runners := a.runners
+	a.mu.Unlock()
+
+	var errs []error
+	for _, runner := range runners {
+		if suspender, ok := runner.Runner.(Suspender); ok {
+			if err := suspender.Resume(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("runner '%s': %w", runner.name(), err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Suspended reports whether the app is suspended.
+func (a App) Suspended() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.suspended
+}
+
+// Suspend suspends the embedded app.
+func (e embeddedApp) Suspend(ctx context.Context) error {
+	return e.App.Suspend(ctx)
+}
+
+// Resume resumes the embedded app.
+func (e embeddedApp) Resume(ctx context.Context) error {
+	return e.App.Resume(ctx)
+}
@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+type suspender struct {
+	name   string
+	mu     *sync.Mutex
+	events *[]string
+	err    error
+}
+
+func (s suspender) Run(ctx context.Context) error {
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func (s suspender) Suspend(context.Context) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	*s.events = append(*s.events, "suspend "+s.name)
+
+	return s.err
+}
+
+func (s suspender) Resume(context.Context) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	*s.events = append(*s.events, "resume "+s.name)
+
+	return nil
+}
+
+func (s suspender) Name() string {
+
+	return s.name
+}
+
+func TestAppSuspend(t *testing.T) {
+
+	var (
+		mu      sync.Mutex
+		events  []string
+		testErr = errors.New("test")
+	)
+	app, err := chariot.New(chariot.With(
+		func() *suspender {
+
+			return &suspender{name: "first", mu: &mu, events: &events}
+		},
+		func() suspender {
+
+			return suspender{name: "second", mu: &mu, events: &events, err: testErr}
+		},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	if err := app.Suspend(context.Background()); !errors.Is(err, chariot.ErrAppNotRunning) {
+		t.Fatal(err)
+	}
+
+	ran := make(chan error, 1)
+	go func() {
+
+		ran <- app.Run()
+	}()
+	<-app.Ready()
+
+	err = app.Suspend(context.Background())
+	if !errors.Is(err, testErr) || !strings.Contains(err.Error(), "second") || !app.Suspended() {
+		t.Fatal(err)
+	}
+	if err := app.Suspend(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Resume(context.Background()); err != nil || app.Suspended() {
+		t.Fatal(err)
+	}
+
+	app.Shutdown()
+	<-ran
+
+	expected := []string{"suspend second", "suspend first", "resume first", "resume second"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatal(events)
+	}
+}
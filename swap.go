@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// Watched observes a component, which may get replaced by the app's Swap method at runtime.
+// Consumers interested in updates, e.g. of a reloaded TLS configuration or rotated credentials,
+// hold a Watched instead of the component itself, and load the current instance when needed.
+type Watched struct {
+	app           App
+	componentType reflect.Type
+}
+
+// Swap replaces an instance of a component at runtime. A valid value is a pointer to the type of
+// the component holding the new instance. Components constructed before the swap keep the instance
+// they were given; the ones constructed afterwards, as well as Retrieve and Watched, observe the
+// new one. Runner and Shutdowner duties stay with the original instance. An error is returned if
+// the app has been shut down (ErrAppClosed) or there is no such a component.
+func (a App) Swap(ptr interface{}) error {
+	if a.isClosed() {
+		return ErrAppClosed
+	}
+
+	value := reflect.ValueOf(ptr).Elem()
+
+	owner, component, found := a.lookup(value.Type())
+	if !found {
+		return fmt.Errorf("%w '%s'", ErrMissingComponent, value.Type())
+	}
+	if owner.rejectNil && isNil(value) {
+		return fmt.Errorf("swapping in a %w of type '%s'", ErrNilComponent, value.Type())
+	}
+
+	// Constructing a lazy component concurrently would otherwise override the swapped instance.
+	owner.mu.RLock()
+	constructor := component.node
+	owner.mu.RUnlock()
+	defer owner.lockLazy(constructor)()
+
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	component.value = reflect.New(value.Type()).Elem()
+	component.value.Set(value)
+	atomic.AddInt64(&owner.overrides, 1)
+	if swapped, ok := owner.swaps[value.Type()]; ok {
+		close(swapped)
+		delete(owner.swaps, value.Type())
+	}
+
+	return nil
+}
+
+// Watch makes a Watched of a component. A valid value is a pointer to the type of the component.
+// An error is returned if there is no such a component.
+func (a App) Watch(ptr interface{}) (*Watched, error) {
+	componentType := reflect.TypeOf(ptr).Elem()
+
+	owner, _, found := a.lookup(componentType)
+	if !found {
+		return nil, fmt.Errorf("%w '%s'", ErrMissingComponent, componentType)
+	}
+
+	return &Watched{
+		app:           owner,
+		componentType: componentType,
+	}, nil
+}
+
+// Load retrieves the current instance of the watched component the way the app's Retrieve method
+// does.
+func (w *Watched) Load(ptr interface{}) bool {
+	if reflect.TypeOf(ptr).Elem() != w.componentType {
+		return false
+	}
+
+	return w.app.Retrieve(ptr)
+}
+
+// Swapped returns a channel that's closed once the watched component is swapped. Call it again
+// after that to wait for the next swap.
+func (w *Watched) Swapped() <-chan struct{} {
+	w.app.mu.Lock()
+	defer w.app.mu.Unlock()
+
+	if w.app.swaps == nil {
+		w.app.swaps = make(map[reflect.Type]chan struct{})
+	}
+	swapped, ok := w.app.swaps[w.componentType]
+	if !ok {
+		swapped = make(chan struct{})
+		w.app.swaps[w.componentType] = swapped
+	}
+
+	return swapped
+}
@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"testing"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestAppSwap(t *testing.T) {
+
+	t.Run("swap", func(t *testing.T) {
+
+		c1 := new(C)
+
+		app, err := chariot.New(chariot.WithComponents(c1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		watched, err := app.Watch(new(*C))
+		if err != nil {
+			t.Fatal(err)
+		}
+		swapped := watched.Swapped()
+
+		c2 := new(C)
+		if err := app.Swap(&c2); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case <-swapped:
+		default:
+			t.FailNow()
+		}
+
+		var c *C
+		switch {
+		case !watched.Load(&c):
+			t.FailNow()
+		case c != c2:
+			t.FailNow()
+		case !app.Retrieve(&c):
+			t.FailNow()
+		case c != c2:
+			t.FailNow()
+		}
+
+		select {
+		case <-watched.Swapped():
+			t.FailNow()
+		default:
+		}
+	})
+
+	t.Run("interface", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.With(func() E {
+
+			return new(F)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		f := new(F)
+		e := E(f)
+		if err := app.Swap(&e); err != nil {
+			t.Fatal(err)
+		}
+
+		var retrieved E
+		if !app.Retrieve(&retrieved) || retrieved != E(f) {
+			t.FailNow()
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		c := new(C)
+		if err := app.Swap(&c); err == nil {
+			t.FailNow()
+		}
+		if _, err := app.Watch(&c); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+
+		c := new(C)
+
+		app, err := chariot.New(chariot.WithComponents(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.Shutdown()
+
+		if err := app.Swap(&c); err != chariot.ErrAppClosed {
+			t.Fatal(err)
+		}
+	})
+}
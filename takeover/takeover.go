@@ -0,0 +1,214 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package takeover passes listening sockets from an app to its successor, so that restarts don't
+// refuse connections. Within a process, e.g. upon the Reload method of an app, the Listeners
+// component of the successor takes over the live listeners of the predecessor: both share the
+// socket till the predecessor is shut down. Across an exec, the listeners are passed to the new
+// process as inherited file descriptors (see the Command method).
+package takeover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rwyyr/chariot"
+)
+
+// EnvListeners is the environment variable describing the listeners inherited by a process, as a
+// comma-separated list of network|address|fd entries.
+const EnvListeners = "CHARIOT_LISTENERS"
+
+// Listeners is a Shutdowner-conformant component handing out listeners, taken over from a
+// predecessor where possible. The listeners are closed once the component is shut down.
+type Listeners struct {
+	mu     sync.Mutex
+	owned  map[string]net.Listener
+	closed bool
+}
+
+type filer interface {
+	File() (*os.File, error)
+}
+
+var (
+	// live are the listeners of the process by network and address, the latest handed out first.
+	live   = make(map[string]net.Listener)
+	liveMu sync.Mutex
+
+	inherited     map[string]*os.File
+	inheritedOnce sync.Once
+)
+
+// Module provides the Listeners component to an app.
+func Module() chariot.Module {
+	return chariot.With(New)
+}
+
+// New makes a component handing out listeners.
+func New() *Listeners {
+	return &Listeners{
+		owned: make(map[string]net.Listener),
+	}
+}
+
+// Listen announces on the address the way the net.Listen function does, unless a live listener of
+// the process or one inherited from the parent process (see the Command method) listens on the
+// same network and address, in which case the listener is taken over.
+func (l *Listeners) Listen(network, address string) (net.Listener, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil, net.ErrClosed
+	}
+
+	key := network + "|" + address
+	if _, found := l.owned[key]; found {
+		return nil, fmt.Errorf("%s listener on '%s' has already been handed out", network, address)
+	}
+
+	listener, err := takeOver(key)
+	if err != nil {
+		return nil, err
+	}
+	if listener == nil {
+		if listener, err = net.Listen(network, address); err != nil {
+			return nil, err
+		}
+	}
+	l.owned[key] = listener
+
+	liveMu.Lock()
+	live[key] = listener
+	liveMu.Unlock()
+
+	return listener, nil
+}
+
+// Command makes a command inheriting the listeners, e.g. to exec a new version of the binary that
+// takes the listeners over once it calls the Listen method with the same networks and addresses.
+func (l *Listeners) Command(name string, args ...string) (*exec.Cmd, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cmd := exec.Command(name, args...)
+	entries := make([]string, 0, len(l.owned))
+	for key, listener := range l.owned {
+		file, err := fileOf(listener)
+		if err != nil {
+			for _, file := range cmd.ExtraFiles {
+				file.Close()
+			}
+
+			return nil, err
+		}
+		// Extra files are inherited as descriptors numbered from 3 on.
+		entries = append(entries, key+"|"+strconv.Itoa(3+len(cmd.ExtraFiles)))
+		cmd.ExtraFiles = append(cmd.ExtraFiles, file)
+	}
+	cmd.Env = append(os.Environ(), EnvListeners+"="+strings.Join(entries, ","))
+
+	return cmd, nil
+}
+
+// Shutdown closes the listeners handed out.
+func (l *Listeners) Shutdown(context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	for key, listener := range l.owned {
+		liveMu.Lock()
+		if live[key] == listener {
+			delete(live, key)
+		}
+		liveMu.Unlock()
+
+		listener.Close()
+	}
+}
+
+// takeOver duplicates the live listener of the process, or adopts the inherited one, of the
+// network and address, if any.
+func takeOver(key string) (net.Listener, error) {
+	liveMu.Lock()
+	predecessor, found := live[key]
+	liveMu.Unlock()
+	if found {
+		// The socket file of a unix listener is to outlive the predecessor.
+		keepSocketFile(predecessor)
+
+		file, err := fileOf(predecessor)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		return net.FileListener(file)
+	}
+
+	inheritedOnce.Do(parseInherited)
+	liveMu.Lock()
+	file, found := inherited[key]
+	delete(inherited, key)
+	liveMu.Unlock()
+	if !found {
+		return nil, nil
+	}
+	defer file.Close()
+
+	return net.FileListener(file)
+}
+
+// parseInherited parses the listeners inherited from the parent process.
+func parseInherited() {
+	inherited = make(map[string]*os.File)
+	for _, entry := range strings.Split(os.Getenv(EnvListeners), ",") {
+		separator := strings.LastIndex(entry, "|")
+		if separator < 0 {
+			continue
+		}
+
+		fd, err := strconv.Atoi(entry[separator+1:])
+		if err != nil {
+			continue
+		}
+		inherited[entry[:separator]] = os.NewFile(uintptr(fd), entry[:separator])
+	}
+}
+
+func fileOf(listener net.Listener) (*os.File, error) {
+	filer, ok := listener.(filer)
+	if !ok {
+		return nil, errors.New("the listener doesn't expose its file")
+	}
+
+	return filer.File()
+}
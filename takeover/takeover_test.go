@@ -0,0 +1,161 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package takeover_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/takeover"
+)
+
+type server struct {
+	listener net.Listener
+}
+
+func newServer(listeners *takeover.Listeners) (*server, error) {
+
+	listener, err := listeners.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	return &server{listener}, nil
+}
+
+func TestListeners(t *testing.T) {
+
+	t.Run("reload", func(t *testing.T) {
+
+		app, err := chariot.New(takeover.Module(), chariot.With(newServer))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var predecessor *server
+		if !app.Retrieve(&predecessor) {
+			t.FailNow()
+		}
+
+		reloaded, err := app.Reload()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reloaded.Shutdown()
+
+		var successor *server
+		if !reloaded.Retrieve(&successor) {
+			t.FailNow()
+		}
+		if successor.listener.Addr().String() != predecessor.listener.Addr().String() {
+			t.Fatal(successor.listener.Addr(), predecessor.listener.Addr())
+		}
+
+		conn, err := net.Dial("tcp", successor.listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		accepted, err := successor.listener.Accept()
+		if err != nil {
+			t.Fatal(err)
+		}
+		accepted.Close()
+	})
+
+	t.Run("handed-out", func(t *testing.T) {
+
+		listeners := takeover.New()
+		defer listeners.Shutdown(context.Background())
+
+		if _, err := listeners.Listen("tcp", "127.0.0.1:0"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := listeners.Listen("tcp", "127.0.0.1:0"); err == nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("exec", func(t *testing.T) {
+
+		listeners := takeover.New()
+		defer listeners.Shutdown(context.Background())
+
+		listener, err := listeners.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd, err := listeners.Command(os.Args[0], "-test.run=TestInheritedListener$")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if env := cmd.Env[len(cmd.Env)-1]; env != takeover.EnvListeners+"=tcp|127.0.0.1:0|3" {
+			t.Fatal(env)
+		}
+		cmd.Env = append(cmd.Env, "TAKEOVER_CHILD=1")
+
+		output, err := cmd.Output()
+		for _, file := range cmd.ExtraFiles {
+			file.Close()
+		}
+		if err != nil {
+			t.Fatal(err, string(output))
+		}
+		if !strings.Contains(string(output), "addr="+listener.Addr().String()+"\n") {
+			t.Fatal(string(output))
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+
+		listeners := takeover.New()
+		listeners.Shutdown(context.Background())
+
+		if _, err := listeners.Listen("tcp", "127.0.0.1:0"); err == nil {
+			t.FailNow()
+		}
+	})
+}
+
+func TestInheritedListener(t *testing.T) {
+
+	if os.Getenv("TAKEOVER_CHILD") == "" {
+		t.Skip("run by the parent process")
+	}
+
+	listeners := takeover.New()
+	defer listeners.Shutdown(context.Background())
+
+	listener, err := listeners.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Printf("addr=%s\n", listener.Addr())
+}
@@ -0,0 +1,206 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package tlsreload keeps TLS certificates of servers up to date without restarts. The Reloader
+// component loads a certificate and its key from files, exposes a *tls.Config serving the live
+// copy, and reloads the files once they change on disk or the process receives SIGHUP. A reload
+// that fails leaves the previous certificate in service.
+package tlsreload
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+const defaultInterval = 10 * time.Second
+
+type (
+	// Reloader is a Runner-conformant component serving a certificate reloaded from files.
+	Reloader struct {
+		certFile string
+		keyFile  string
+		signals  *chariot.Signals
+		options  options
+
+		mu       sync.RWMutex
+		cert     *tls.Certificate
+		modified time.Time
+	}
+
+	// Option stands for an option of a reloader.
+	Option func(*options)
+
+	options struct {
+		interval time.Duration
+		handler  func(error)
+	}
+)
+
+// Module provides a reloader of the certificate and the key files to an app. The reloader reloads
+// the files upon SIGHUP delivered by the app's Signals component.
+func Module(certFile, keyFile string, funcOptions ...Option) chariot.Module {
+	return chariot.With(func(signals *chariot.Signals) (*Reloader, error) {
+		return New(signals, certFile, keyFile, funcOptions...)
+	})
+}
+
+// WithInterval provides a replacement to the default interval of 10 seconds the files are checked
+// for modifications at. A non-positive interval disables the checks, so the files are only
+// reloaded upon SIGHUP or explicitly.
+func WithInterval(interval time.Duration) Option {
+	return func(options *options) {
+		options.interval = interval
+	}
+}
+
+// WithErrorHandler provides a handler of errors of reloads triggered by the reloader itself, which
+// are ignored otherwise.
+func WithErrorHandler(handler func(error)) Option {
+	return func(options *options) {
+		options.handler = handler
+	}
+}
+
+// New makes a reloader loading the certificate and the key from the files. Signals, if not nil,
+// deliver SIGHUP to the reloader. An error is returned if the files fail to load.
+func New(
+	signals *chariot.Signals,
+	certFile string,
+	keyFile string,
+	funcOptions ...Option,
+) (*Reloader, error) {
+	options := options{
+		interval: defaultInterval,
+		handler:  func(error) {},
+	}
+	for _, option := range funcOptions {
+		option(&options)
+	}
+
+	reloader := Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		signals:  signals,
+		options:  options,
+	}
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+
+	return &reloader, nil
+}
+
+// Config makes a TLS config serving the live certificate.
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		GetCertificate: r.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// GetCertificate reports the live certificate; it's meant for the field of tls.Config of the same
+// name.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// Reload loads the files replacing the live certificate. The live certificate stays intact if the
+// files fail to load.
+func (r *Reloader) Reload() error {
+	modified := r.lastModified()
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+	r.modified = modified
+
+	return nil
+}
+
+// Run reloads the files once they're modified or SIGHUP is received, till the context is
+// cancelled.
+func (r *Reloader) Run(ctx context.Context) error {
+	var hangups <-chan os.Signal
+	if r.signals != nil && reloadSignal != nil {
+		received, stop := r.signals.Notify(reloadSignal)
+		defer stop()
+		hangups = received
+	}
+
+	var ticks <-chan time.Time
+	if r.options.interval > 0 {
+		ticker := time.NewTicker(r.options.interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-hangups:
+			if !ok {
+				hangups = nil
+				continue
+			}
+		case <-ticks:
+			r.mu.RLock()
+			unmodified := !r.lastModified().After(r.modified)
+			r.mu.RUnlock()
+			if unmodified {
+				continue
+			}
+		}
+
+		if err := r.Reload(); err != nil {
+			r.options.handler(err)
+		}
+	}
+}
+
+// lastModified reports the time either of the files was last modified at, or the zero time if
+// neither can be inspected.
+func (r *Reloader) lastModified() time.Time {
+	var modified time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+	}
+
+	return modified
+}
@@ -0,0 +1,157 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tlsreload_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+	"github.com/rwyyr/chariot/tlsreload"
+)
+
+func writeCert(t *testing.T, certFile, keyFile string, modified time.Time) []byte {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	for file, content := range map[string][]byte{certFile: certPEM, keyFile: keyPEM} {
+		if err := os.WriteFile(file, content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return cert
+}
+
+func TestReloader(t *testing.T) {
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	t.Run("missing-files", func(t *testing.T) {
+
+		if _, err := tlsreload.New(nil, certFile, keyFile); err == nil {
+			t.FailNow()
+		}
+	})
+
+	initial := writeCert(t, certFile, keyFile, time.Now().Add(-time.Hour))
+
+	app, err := chariot.New(
+		tlsreload.Module(certFile, keyFile, tlsreload.WithInterval(time.Millisecond)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown()
+
+	var reloader *tlsreload.Reloader
+	if !app.Retrieve(&reloader) {
+		t.FailNow()
+	}
+	live := func() []byte {
+
+		cert, err := reloader.Config().GetCertificate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return cert.Certificate[0]
+	}
+
+	t.Run("initial", func(t *testing.T) {
+
+		if !bytes.Equal(live(), initial) {
+			t.FailNow()
+		}
+	})
+
+	t.Run("modification", func(t *testing.T) {
+
+		ran := make(chan error, 1)
+		go func() {
+
+			ran <- app.Run()
+		}()
+		<-app.Ready()
+
+		reloaded := writeCert(t, certFile, keyFile, time.Now())
+		for deadline := time.Now().Add(5 * time.Second); !bytes.Equal(live(), reloaded); {
+			if time.Now().After(deadline) {
+				t.Fatal("the certificate hasn't been reloaded")
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		app.Shutdown()
+		if err := <-ran; err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		before := live()
+		if err := os.WriteFile(certFile, []byte("garbage"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := reloader.Reload(); err == nil {
+			t.FailNow()
+		}
+		if !bytes.Equal(live(), before) {
+			t.FailNow()
+		}
+	})
+}
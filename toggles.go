@@ -0,0 +1,273 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// DebugToggles is a set of toggles of the framework's debugging aids, modifiable at runtime, e.g.
+// by an admin endpoint, rather than only when an app is constructed. The toggles are:
+//
+//	chariot.verbose        a boolean making resolutions of components logged, along with hints
+//	                       on the closest types when a component is missing
+//	chariot.deterministic  a boolean making inits invoked one by one regardless of the
+//	                       WithParallelInits option
+//	chariot.slow-init      a duration replacing the threshold of the WithSlowInitWarning option;
+//	                       slow initializers are logged if the option isn't provided
+//
+// The toggles take effect on the initializers invoked afterwards, e.g. lazy constructors and the
+// ones of scopes, which share the toggles of their app. An app is prepackaged with the component;
+// the toggles seeded before the app is initialized, e.g. from the command line, are provided via
+// the WithDebugToggles option.
+type DebugToggles struct {
+	verbose       int32
+	deterministic int32
+	slowInit      int64
+}
+
+// toggleValue adapts a toggle to the flag.Value interface.
+type toggleValue struct {
+	get    func() string
+	set    func(string) error
+	isBool bool
+}
+
+var debugTogglesType = reflect.TypeOf((*DebugToggles)(nil))
+
+// WithDebugToggles provides the toggles an app is prepackaged with in place of the ones of its own,
+// so the toggles seeded beforehand, e.g. by the flags (see the RegisterFlags method), take effect
+// on the initialization of the app as well. Scopes of the app share the toggles regardless.
+func WithDebugToggles(toggles *DebugToggles) Option {
+	return func(options *options) {
+		options.toggles = toggles
+	}
+}
+
+// Verbose reports whether resolutions of components are logged.
+func (t *DebugToggles) Verbose() bool {
+	return atomic.LoadInt32(&t.verbose) == 1
+}
+
+// SetVerbose makes resolutions of components logged or not.
+func (t *DebugToggles) SetVerbose(verbose bool) {
+	atomic.StoreInt32(&t.verbose, boolToInt32(verbose))
+}
+
+// DeterministicOrder reports whether inits are invoked one by one.
+func (t *DebugToggles) DeterministicOrder() bool {
+	return atomic.LoadInt32(&t.deterministic) == 1
+}
+
+// SetDeterministicOrder makes inits invoked one by one or not.
+func (t *DebugToggles) SetDeterministicOrder(deterministic bool) {
+	atomic.StoreInt32(&t.deterministic, boolToInt32(deterministic))
+}
+
+// SlowInitThreshold reports the threshold replacing the one of the WithSlowInitWarning option, or
+// 0 if there's none.
+func (t *DebugToggles) SlowInitThreshold() time.Duration {
+	return time.Duration(atomic.LoadInt64(&t.slowInit))
+}
+
+// SetSlowInitThreshold replaces the threshold of the WithSlowInitWarning option; a non-positive
+// threshold restores the one of the option.
+func (t *DebugToggles) SetSlowInitThreshold(threshold time.Duration) {
+	atomic.StoreInt64(&t.slowInit, int64(threshold))
+}
+
+// RegisterFlags registers the toggles as flags of the set, e.g. flag.CommandLine, so that they're
+// set from the command line and visible to flag.Lookup. The zero value of the toggles is ready for
+// use, so the flags may be registered before an app is initialized:
+//
+//	toggles := new(chariot.DebugToggles)
+//	toggles.RegisterFlags(flag.CommandLine)
+//	flag.Parse()
+//
+//	app, err := chariot.New(chariot.WithDebugToggles(toggles), ...)
+func (t *DebugToggles) RegisterFlags(flags *flag.FlagSet) {
+	values := t.values()
+	for _, name := range t.Names() {
+		flags.Var(values[name], name, "chariot debug toggle")
+	}
+}
+
+// Names reports the names of the toggles in the lexical order.
+func (t *DebugToggles) Names() []string {
+	var names []string
+	for name := range t.values() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get reports the value of the toggle of the name.
+func (t *DebugToggles) Get(name string) (string, bool) {
+	value, ok := t.values()[name]
+	if !ok {
+		return "", false
+	}
+
+	return value.String(), true
+}
+
+// Set parses the value of the toggle of the name, e.g. Set("chariot.slow-init", "100ms").
+func (t *DebugToggles) Set(name, raw string) error {
+	value, ok := t.values()[name]
+	if !ok {
+		return fmt.Errorf("unknown debug toggle '%s'", name)
+	}
+	if err := value.Set(raw); err != nil {
+		return fmt.Errorf("debug toggle %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (t *DebugToggles) values() map[string]toggleValue {
+	return map[string]toggleValue{
+		"chariot.verbose": {
+			get: func() string {
+				return strconv.FormatBool(t.Verbose())
+			},
+			set: func(raw string) error {
+				verbose, err := strconv.ParseBool(raw)
+				if err != nil {
+					return err
+				}
+				t.SetVerbose(verbose)
+
+				return nil
+			},
+			isBool: true,
+		},
+		"chariot.deterministic": {
+			get: func() string {
+				return strconv.FormatBool(t.DeterministicOrder())
+			},
+			set: func(raw string) error {
+				deterministic, err := strconv.ParseBool(raw)
+				if err != nil {
+					return err
+				}
+				t.SetDeterministicOrder(deterministic)
+
+				return nil
+			},
+			isBool: true,
+		},
+		"chariot.slow-init": {
+			get: func() string {
+				return t.SlowInitThreshold().String()
+			},
+			set: func(raw string) error {
+				threshold, err := time.ParseDuration(raw)
+				if err != nil {
+					return err
+				}
+				t.SetSlowInitThreshold(threshold)
+
+				return nil
+			},
+		},
+	}
+}
+
+// String reports the value of the toggle.
+func (v toggleValue) String() string {
+	if v.get == nil {
+		return ""
+	}
+
+	return v.get()
+}
+
+// Set parses the value of the toggle.
+func (v toggleValue) Set(raw string) error {
+	return v.set(raw)
+}
+
+// IsBoolFlag tells the flag package the toggle is set by the flag alone, e.g. -chariot.verbose.
+func (v toggleValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+func (a App) setDebugTogglesComponent(options options) {
+	switch {
+	case a.parent.Valid():
+		a.toggles = a.parent.toggles
+	case options.toggles != nil:
+		a.toggles = options.toggles
+	default:
+		a.toggles = new(DebugToggles)
+	}
+	if !a.parent.Valid() {
+		if options.verbose {
+			a.toggles.SetVerbose(true)
+		}
+		if options.deterministic {
+			a.toggles.SetDeterministicOrder(true)
+		}
+	}
+	a.components[debugTogglesType] = &component{
+		value: reflect.ValueOf(a.toggles),
+	}
+}
+
+// reportSlowInit reports an initializer that took longer than the threshold of the
+// WithSlowInitWarning option, or the one of the debug toggles, if any, to be invoked.
+func (a App) reportSlowInit(initializer *node, took time.Duration) {
+	override := a.toggles.SlowInitThreshold()
+	if a.slowInit == nil && override <= 0 {
+		return
+	}
+
+	threshold, handler := override, func(name string, took time.Duration) {
+		a.logf("initializer '%s' took %s\n", name, took)
+	}
+	if a.slowInit != nil {
+		handler = a.slowInit.handler
+		if override <= 0 {
+			threshold = a.slowInit.threshold
+		}
+	}
+	if took > threshold {
+		handler(funcName(initializer.initializer), took)
+	}
+}
+
+func boolToInt32(value bool) int32 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,176 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestDebugToggles(t *testing.T) {
+
+	t.Run("flags", func(t *testing.T) {
+
+		app, err := chariot.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var toggles *chariot.DebugToggles
+		if !app.Retrieve(&toggles) {
+			t.FailNow()
+		}
+
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		toggles.RegisterFlags(flags)
+		if err := flags.Parse([]string{"-chariot.verbose", "-chariot.slow-init=1s"}); err != nil {
+			t.Fatal(err)
+		}
+		if !toggles.Verbose() || toggles.SlowInitThreshold() != time.Second {
+			t.FailNow()
+		}
+
+		if err := flags.Lookup("chariot.deterministic").Value.Set("true"); err != nil {
+			t.Fatal(err)
+		}
+		if value, ok := toggles.Get("chariot.deterministic"); !ok || value != "true" {
+			t.Fatal(value)
+		}
+		if err := toggles.Set("chariot.slow-init", "soon"); err == nil {
+			t.FailNow()
+		}
+		if toggles.SlowInitThreshold() != time.Second {
+			t.FailNow()
+		}
+	})
+
+	t.Run("seeded", func(t *testing.T) {
+
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer log.SetOutput(os.Stderr)
+
+		seeded := new(chariot.DebugToggles)
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		seeded.RegisterFlags(flags)
+		if err := flags.Parse([]string{"-chariot.verbose"}); err != nil {
+			t.Fatal(err)
+		}
+
+		app, err := chariot.New(
+			chariot.WithDebugToggles(seeded),
+			chariot.With(func() *C {
+
+				return new(C)
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var toggles *chariot.DebugToggles
+		if !app.Retrieve(&toggles) || toggles != seeded {
+			t.FailNow()
+		}
+		if !strings.Contains(logs.String(), "invoked initializer") {
+			t.Fatal(logs.String())
+		}
+	})
+
+	t.Run("slow-init", func(t *testing.T) {
+
+		var slow []string
+		app, err := chariot.New(
+			chariot.WithSlowInitWarning(time.Hour, func(name string, _ time.Duration) {
+
+				slow = append(slow, name)
+			}),
+			chariot.With(chariot.Lazy(func() *C {
+
+				time.Sleep(2 * time.Millisecond)
+
+				return new(C)
+			})),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var toggles *chariot.DebugToggles
+		if !app.Retrieve(&toggles) {
+			t.FailNow()
+		}
+		toggles.SetSlowInitThreshold(time.Millisecond)
+
+		var c *C
+		if !app.Retrieve(&c) || len(slow) != 1 {
+			t.Fatal(slow)
+		}
+	})
+
+	t.Run("miss-hints", func(t *testing.T) {
+
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer log.SetOutput(os.Stderr)
+
+		app, err := chariot.New(chariot.With(func() C {
+
+			return C{}
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var c *C
+		if app.Retrieve(&c) || logs.Len() != 0 {
+			t.Fatal(logs.String())
+		}
+
+		var toggles *chariot.DebugToggles
+		if !app.Retrieve(&toggles) {
+			t.FailNow()
+		}
+		toggles.SetVerbose(true)
+
+		if app.Retrieve(&c) {
+			t.FailNow()
+		}
+		if hint := logs.String(); !strings.Contains(hint, "'*chariot_test.C'") ||
+			!strings.Contains(hint, "closest types: 'chariot_test.C'") ||
+			!strings.Contains(hint, "'chariot_test.C' is provided rather than '*chariot_test.C'") {
+			t.Fatal(hint)
+		}
+	})
+}
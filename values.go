@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Values holds simple keyed values, e.g. the name of a service or the region it's deployed to,
+// that are needed across many modules yet don't deserve a configuration struct of their own (see
+// the WithValues option). An app is prepackaged with the component; a scope's one holds the values
+// of its parent along with its own.
+type Values struct {
+	values map[string]interface{}
+}
+
+var valuesType = reflect.TypeOf(Values{})
+
+// WithValues provides keyed values to the prepackaged Values component. The values of several
+// options are merged, a later value of the same key taking precedence.
+func WithValues(values map[string]interface{}) Option {
+	return func(options *options) {
+		if options.values == nil {
+			options.values = make(map[string]interface{}, len(values))
+		}
+		for key, value := range values {
+			options.values[key] = value
+		}
+	}
+}
+
+// Get reports the value of the key.
+func (v Values) Get(key string) (interface{}, bool) {
+	value, ok := v.values[key]
+
+	return value, ok
+}
+
+// Lookup retrieves the value of the key. A valid value is a pointer to the type of the value or an
+// interface it implements. Nothing is retrieved if there's no value of the key or it's of another
+// type.
+func (v Values) Lookup(key string, ptr interface{}) bool {
+	value, ok := v.values[key]
+	if !ok {
+		return false
+	}
+
+	target := reflect.ValueOf(ptr)
+	if target.Kind() != reflect.Ptr || target.IsNil() || value == nil {
+		return false
+	}
+	if !reflect.TypeOf(value).AssignableTo(target.Elem().Type()) {
+		return false
+	}
+	target.Elem().Set(reflect.ValueOf(value))
+
+	return true
+}
+
+// String reports the value of the key if it's a string, or an empty string otherwise.
+func (v Values) String(key string) string {
+	value, _ := v.values[key].(string)
+
+	return value
+}
+
+// Keys reports the keys of the values in order.
+func (v Values) Keys() []string {
+	keys := make([]string, 0, len(v.values))
+	for key := range v.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (a App) setValuesComponent(values map[string]interface{}) {
+	merged := make(map[string]interface{}, len(values))
+	if a.parent.Valid() {
+		for key, value := range a.parent.values.values {
+			merged[key] = value
+		}
+	}
+	for key, value := range values {
+		merged[key] = value
+	}
+
+	a.values = Values{values: merged}
+	a.components[valuesType] = &component{
+		value: reflect.ValueOf(a.values),
+	}
+}
@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2023 Roman Homoliako
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chariot_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rwyyr/chariot"
+)
+
+func TestWithValues(t *testing.T) {
+
+	t.Run("lookup", func(t *testing.T) {
+
+		var values chariot.Values
+		app, err := chariot.New(
+			chariot.WithValues(map[string]interface{}{"service": "api", "region": "eu"}),
+			chariot.WithValues(map[string]interface{}{"region": "us", "timeout": time.Second}),
+			chariot.With(func(v chariot.Values) {
+
+				values = v
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		var (
+			timeout time.Duration
+			region  int
+		)
+		switch {
+		case values.String("service") != "api" || values.String("region") != "us":
+			t.Fatal(values.Keys())
+		case !values.Lookup("timeout", &timeout) || timeout != time.Second:
+			t.Fatal(timeout)
+		case values.Lookup("region", &region) || values.Lookup("missing", &region):
+			t.Fatal(region)
+		case !reflect.DeepEqual(values.Keys(), []string{"region", "service", "timeout"}):
+			t.Fatal(values.Keys())
+		}
+	})
+
+	t.Run("scope", func(t *testing.T) {
+
+		app, err := chariot.New(chariot.WithValues(map[string]interface{}{
+			"service": "api",
+			"region":  "eu",
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.Shutdown()
+
+		scope, err := app.Scope(chariot.WithValues(map[string]interface{}{"region": "us"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer scope.Shutdown()
+
+		var values chariot.Values
+		if !scope.Retrieve(&values) {
+			t.FailNow()
+		}
+		if values.String("service") != "api" || values.String("region") != "us" {
+			t.Fatal(values.Keys())
+		}
+	})
+}